@@ -1,8 +1,56 @@
 package config
 
+import _ "embed"
+
+// DefaultPasswordBlocklist is checked when no PASSWORD_BLOCKLIST_FILE is
+// configured.
+//
+//go:embed password-blocklist.txt
+var DefaultPasswordBlocklist string
+
 type AppConfig struct {
-	Port            int
-	CookieSecretKey string
+	Port                          int
+	CookieSecretKey               string
+	TrustedProxies                []string
+	SessionTTLHours               int
+	SessionCleanupIntervalMinutes int
+	DefaultUserRole               string
+	FrontendURL                   string
+	PasswordResetTTLMinutes       int
+	EmailVerificationTTLHours     int
+	UnverifiedBlockedFeatures     []string
+	LoginMaxAttempts              int
+	LoginIPMaxAttempts            int
+	LoginBackoffBaseSeconds       int
+	LoginBackoffMaxSeconds        int
+	LoginLockoutMinutes           int
+	GuestSessionMaxPerIP          int
+	GuestSessionWindowMinutes     int
+	AccessTokenSecret             string
+	AccessTokenTTLMinutes         int
+	RefreshTokenTTLDays           int
+	MFAIssuer                     string
+	MFARequiredRoles              []string
+	MFAChallengeTTLMinutes        int
+	PublicRegistrationEnabled     bool
+	InvitationTTLHours            int
+	PasswordMinLength             int
+	PasswordMaxLength             int
+	PasswordRequireUppercase      bool
+	PasswordRequireLowercase      bool
+	PasswordRequireDigit          bool
+	PasswordRequireSymbol         bool
+	PasswordBlocklistEnabled      bool
+	PasswordBlocklistFile         string
+	PasswordCheckPersonalInfo     bool
+	PasswordHashAlgorithm         string
+	PasswordBcryptCost            int
+	PasswordArgon2MemoryKiB       int
+	PasswordArgon2Iterations      int
+	PasswordArgon2Parallelism     int
+	DefaultRestaurantId           string
+	DefaultRestaurantAutoJoin     bool
+	PrivacyCustomerRestaurantId   string
 }
 
 func LoadAppConfig() *AppConfig {
@@ -10,6 +58,60 @@ func LoadAppConfig() *AppConfig {
 
 	config.Port = getEnvAsInt("PORT", 8080)
 	config.CookieSecretKey = getEnvOrDefault("COOKIE_SECRET_KEY", "secret-cookie")
+	// X-Forwarded-For is only believed from these addresses or CIDR ranges.
+	config.TrustedProxies = getEnvAsList("TRUSTED_PROXIES", []string{})
+	config.SessionTTLHours = getEnvAsInt("SESSION_TTL_HOURS", 24*7)
+	config.SessionCleanupIntervalMinutes = getEnvAsInt("SESSION_CLEANUP_INTERVAL_MINUTES", 60)
+	// Role of self registered accounts, owner and manager are refused.
+	config.DefaultUserRole = getEnvOrDefault("DEFAULT_USER_ROLE", "waiter")
+	config.FrontendURL = getEnvOrDefault("FRONTEND_URL", "http://localhost:3000")
+	config.PasswordResetTTLMinutes = getEnvAsInt("PASSWORD_RESET_TTL_MINUTES", 60)
+	config.EmailVerificationTTLHours = getEnvAsInt("EMAIL_VERIFICATION_TTL_HOURS", 48)
+	// Permission names unverified accounts may not use, "*" blocks every guarded route.
+	config.UnverifiedBlockedFeatures = getEnvAsList("UNVERIFIED_BLOCKED_FEATURES", []string{
+		"users.manage", "roles.manage", "payments.manage", "settings.manage",
+	})
+	config.LoginMaxAttempts = getEnvAsInt("LOGIN_MAX_ATTEMPTS", 5)
+	config.LoginIPMaxAttempts = getEnvAsInt("LOGIN_IP_MAX_ATTEMPTS", 20)
+	config.LoginBackoffBaseSeconds = getEnvAsInt("LOGIN_BACKOFF_BASE_SECONDS", 1)
+	config.LoginBackoffMaxSeconds = getEnvAsInt("LOGIN_BACKOFF_MAX_SECONDS", 60)
+	config.LoginLockoutMinutes = getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 15)
+	config.GuestSessionMaxPerIP = getEnvAsInt("GUEST_SESSION_MAX_PER_IP", 10)
+	config.GuestSessionWindowMinutes = getEnvAsInt("GUEST_SESSION_WINDOW_MINUTES", 60)
+	// Signs access tokens and MFA challenges, required, see services.CheckAccessTokenSecret.
+	config.AccessTokenSecret = getEnvOrDefault("ACCESS_TOKEN_SECRET", "")
+	config.AccessTokenTTLMinutes = getEnvAsInt("ACCESS_TOKEN_TTL_MINUTES", 15)
+	config.RefreshTokenTTLDays = getEnvAsInt("REFRESH_TOKEN_TTL_DAYS", 30)
+	config.MFAIssuer = getEnvOrDefault("MFA_ISSUER", "Restaurant")
+	config.MFARequiredRoles = getEnvAsList("MFA_REQUIRED_ROLES", []string{})
+	config.MFAChallengeTTLMinutes = getEnvAsInt("MFA_CHALLENGE_TTL_MINUTES", 5)
+	config.PublicRegistrationEnabled = getEnvAsBool("PUBLIC_REGISTRATION_ENABLED", true)
+	config.InvitationTTLHours = getEnvAsInt("INVITATION_TTL_HOURS", 72)
+	config.PasswordMinLength = getEnvAsInt("PASSWORD_MIN_LENGTH", 8)
+	// bcrypt only looks at the first 72 bytes.
+	config.PasswordMaxLength = getEnvAsInt("PASSWORD_MAX_LENGTH", 72)
+	config.PasswordRequireUppercase = getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", false)
+	config.PasswordRequireLowercase = getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", false)
+	config.PasswordRequireDigit = getEnvAsBool("PASSWORD_REQUIRE_DIGIT", false)
+	config.PasswordRequireSymbol = getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false)
+	config.PasswordBlocklistEnabled = getEnvAsBool("PASSWORD_BLOCKLIST_ENABLED", true)
+	// One password per line, an empty path uses DefaultPasswordBlocklist.
+	config.PasswordBlocklistFile = getEnvOrDefault("PASSWORD_BLOCKLIST_FILE", "")
+	config.PasswordCheckPersonalInfo = getEnvAsBool("PASSWORD_CHECK_PERSONAL_INFO", true)
+	// Existing hashes are upgraded on the next successful login.
+	config.PasswordHashAlgorithm = getEnvOrDefault("PASSWORD_HASH_ALGORITHM", "bcrypt")
+	config.PasswordBcryptCost = getEnvAsInt("PASSWORD_BCRYPT_COST", 12)
+	config.PasswordArgon2MemoryKiB = getEnvAsInt("PASSWORD_ARGON2_MEMORY_KIB", 64*1024)
+	config.PasswordArgon2Iterations = getEnvAsInt("PASSWORD_ARGON2_ITERATIONS", 3)
+	config.PasswordArgon2Parallelism = getEnvAsInt("PASSWORD_ARGON2_PARALLELISM", 2)
+	// Restaurant created by the migrations for existing data.
+	config.DefaultRestaurantId = getEnvOrDefault("DEFAULT_RESTAURANT_ID", "00000000-0000-0000-0000-000000000001")
+	// Self registered and OIDC signed up accounts only join the default
+	// restaurant when enabled, otherwise they wait to be added or invited.
+	config.DefaultRestaurantAutoJoin = getEnvAsBool("DEFAULT_RESTAURANT_AUTO_JOIN", false)
+	// Customers belong to no restaurant, their data requests are processed
+	// by the operator running this restaurant.
+	config.PrivacyCustomerRestaurantId = getEnvOrDefault("PRIVACY_CUSTOMER_RESTAURANT_ID", config.DefaultRestaurantId)
 
 	return config
 }
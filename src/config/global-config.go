@@ -3,17 +3,22 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 type GlobalConfig struct {
-	App *AppConfig
-	DB  *DBConfig
+	App  *AppConfig
+	DB   *DBConfig
+	Mail *MailConfig
+	OIDC *OIDCConfig
 }
 
 func LoadGlobalConfig() *GlobalConfig {
 	return &GlobalConfig{
-		App: LoadAppConfig(),
-		DB:  LoadDBConfig(),
+		App:  LoadAppConfig(),
+		DB:   LoadDBConfig(),
+		Mail: LoadMailConfig(),
+		OIDC: LoadOIDCConfig(),
 	}
 }
 
@@ -34,3 +39,29 @@ func getEnvAsInt(key string, defaultValue int) int {
 
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if envValue := os.Getenv(key); envValue != "" {
+		if boolEnvValue, err := strconv.ParseBool(envValue); err == nil {
+			return boolEnvValue
+		}
+	}
+
+	return defaultValue
+}
+
+func getEnvAsList(key string, defaultValue []string) []string {
+	envValue := os.Getenv(key)
+	if envValue == "" {
+		return defaultValue
+	}
+
+	values := []string{}
+	for _, value := range strings.Split(envValue, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,17 @@
+package config
+
+type MailConfig struct {
+	Driver    string
+	From      string
+	OutputDir string
+}
+
+func LoadMailConfig() *MailConfig {
+	config := &MailConfig{}
+
+	config.Driver = getEnvOrDefault("MAIL_DRIVER", "log")
+	config.From = getEnvOrDefault("MAIL_FROM", "no-reply@restaurant.local")
+	config.OutputDir = getEnvOrDefault("MAIL_OUTPUT_DIR", "tmp/mail")
+
+	return config
+}
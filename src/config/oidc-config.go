@@ -0,0 +1,58 @@
+package config
+
+import "strings"
+
+type OIDCProviderConfig struct {
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+type OIDCConfig struct {
+	// RedirectBaseURL is the public URL of this API, the callback for a
+	// provider is <RedirectBaseURL>/api/auth/oidc/<name>/callback.
+	RedirectBaseURL string
+	StateTTLMinutes int
+	// AllowSignup creates accounts for unknown identities whose verified
+	// email is not taken, otherwise only identities that users linked
+	// themselves can sign in. It has no effect while public registration
+	// is disabled.
+	AllowSignup bool
+	Providers   map[string]*OIDCProviderConfig
+}
+
+func LoadOIDCConfig() *OIDCConfig {
+	config := &OIDCConfig{}
+
+	config.RedirectBaseURL = getEnvOrDefault("OIDC_REDIRECT_BASE_URL", "http://localhost:8080")
+	config.StateTTLMinutes = getEnvAsInt("OIDC_STATE_TTL_MINUTES", 10)
+	config.AllowSignup = getEnvAsBool("OIDC_ALLOW_SIGNUP", false)
+	config.Providers = map[string]*OIDCProviderConfig{}
+
+	// Each provider listed in OIDC_PROVIDERS reads OIDC_<NAME>_ISSUER,
+	// OIDC_<NAME>_CLIENT_ID and so on.
+	for _, name := range getEnvAsList("OIDC_PROVIDERS", []string{}) {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+		provider := &OIDCProviderConfig{
+			Name:         name,
+			DisplayName:  getEnvOrDefault(prefix+"DISPLAY_NAME", name),
+			Issuer:       strings.TrimRight(getEnvOrDefault(prefix+"ISSUER", ""), "/"),
+			ClientID:     getEnvOrDefault(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnvOrDefault(prefix+"CLIENT_SECRET", ""),
+			Scopes:       getEnvAsList(prefix+"SCOPES", []string{"openid", "email", "profile"}),
+		}
+
+		if provider.Issuer == "" || provider.ClientID == "" {
+			continue
+		}
+
+		config.Providers[name] = provider
+	}
+
+	return config
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyController struct {
+	roleRepo      *repositories.RoleRepository
+	apiKeyService *services.APIKeyService
+	auditService  *services.AuditService
+	ctx           *models.AppContext
+}
+
+func NewAPIKeyController(ctx *models.AppContext) *APIKeyController {
+	return &APIKeyController{
+		roleRepo:      repositories.NewRoleRepository(ctx.DB),
+		apiKeyService: services.NewAPIKeyService(ctx),
+		auditService:  services.NewAuditService(ctx),
+		ctx:           ctx,
+	}
+}
+
+// CreateAPIKey returns the plaintext key once, only its hash is stored.
+// Callers can only grant scopes their own role has.
+func (ac *APIKeyController) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	permissions, err := ac.roleRepo.GetPermissionsByRole(user.Role)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if err := ac.validateCreateRequest(&req, permissions); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	plain, key, err := ac.apiKeyService.Create(user, middlewares.GetCurrentRestaurant(r).Id, strings.TrimSpace(req.Name), req.Scopes, req.ExpiresAt)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating API key",
+		})
+		return
+	}
+
+	ac.auditService.Record(r, user, models.AuditActionAPIKeyCreated, models.AuditTargetAPIKey, key.Id.String(), map[string]any{
+		"name":       key.Name,
+		"scopes":     key.Scopes,
+		"expires_at": key.ExpiresAt,
+	})
+
+	utils.WriteJSON(w, http.StatusCreated, models.APIKeyCreatedResponse{
+		Success: true,
+		Message: "Copy the key now, it will not be shown again",
+		Key:     plain,
+		APIKey:  key,
+	})
+}
+
+func (ac *APIKeyController) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	keys, err := ac.apiKeyService.List(middlewares.GetCurrentRestaurant(r).Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.APIKeysResponse{
+		Success: true,
+		APIKeys: keys,
+	})
+}
+
+func (ac *APIKeyController) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	keyId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid API key id",
+		})
+		return
+	}
+
+	revoked, err := ac.apiKeyService.Revoke(middlewares.GetCurrentRestaurant(r).Id, keyId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if !revoked {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "API key not found",
+		})
+		return
+	}
+
+	ac.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionAPIKeyRevoked, models.AuditTargetAPIKey, keyId.String(), nil)
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "API key revoked",
+	})
+}
+
+func (ac *APIKeyController) validateCreateRequest(req *models.CreateAPIKeyRequest, permissions []string) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("name must be no more than 100 characters long")
+	}
+
+	if len(req.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+
+	for _, scope := range req.Scopes {
+		if !models.IsValidAPIKeyScope(scope) {
+			return fmt.Errorf("scope %q cannot be granted to API keys", scope)
+		}
+
+		granted := false
+		for _, permission := range permissions {
+			if permission == scope {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			return fmt.Errorf("you cannot grant scope %q", scope)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditController struct {
+	auditService *services.AuditService
+	ctx          *models.AppContext
+}
+
+func NewAuditController(ctx *models.AppContext) *AuditController {
+	return &AuditController{
+		auditService: services.NewAuditService(ctx),
+		ctx:          ctx,
+	}
+}
+
+// ListEntries filters by actor_id, action, target_type, target_id and a
+// from/to range given as RFC 3339 timestamps or YYYY-MM-DD dates.
+func (ac *AuditController) ListEntries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := models.AuditLogFilter{
+		RestaurantId: middlewares.GetCurrentRestaurant(r).Id,
+		Action:       strings.TrimSpace(query.Get("action")),
+		TargetType:   strings.TrimSpace(query.Get("target_type")),
+		TargetId:     strings.TrimSpace(query.Get("target_id")),
+	}
+
+	if value := query.Get("actor_id"); value != "" {
+		actorId, err := uuid.Parse(value)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid actor_id",
+			})
+			return
+		}
+		filter.ActorId = &actorId
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid from, use RFC 3339 or YYYY-MM-DD",
+		})
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid to, use RFC 3339 or YYYY-MM-DD",
+		})
+		return
+	}
+
+	page, pageSize := utils.GetPagination(r)
+
+	entries, total, err := ac.auditService.List(filter, pageSize, (page-1)*pageSize)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.AuditLogResponse{
+		Success:  true,
+		Entries:  entries,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+
+	parsed, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
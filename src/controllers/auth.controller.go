@@ -2,24 +2,54 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"net"
 	"net/http"
+	"restaurant-backend/src/middlewares"
 	"restaurant-backend/src/models"
 	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
 	"restaurant-backend/src/utils"
+	"strconv"
 	"strings"
-	"time"
+
+	"github.com/google/uuid"
 )
 
 type AuthController struct {
-	userRepo *repositories.UserRepository
-	ctx      *models.AppContext
+	userRepo            *repositories.UserRepository
+	roleRepo            *repositories.RoleRepository
+	restaurantRepo      *repositories.RestaurantRepository
+	sessionService      *services.SessionService
+	verificationService *services.EmailVerificationService
+	throttleService     *services.LoginThrottleService
+	authService         *services.AuthService
+	tokenService        *services.TokenService
+	twoFactorService    *services.TwoFactorService
+	passwordPolicy      *services.PasswordPolicyService
+	auditService        *services.AuditService
+	restaurantService   *services.RestaurantService
+	ctx                 *models.AppContext
 }
 
 func NewAuthController(ctx *models.AppContext) *AuthController {
 	return &AuthController{
-		userRepo: repositories.NewUserRepository(ctx.DB),
-		ctx:      ctx,
+		userRepo:            repositories.NewUserRepository(ctx.DB),
+		roleRepo:            repositories.NewRoleRepository(ctx.DB),
+		restaurantRepo:      repositories.NewRestaurantRepository(ctx.DB),
+		sessionService:      services.NewSessionService(ctx),
+		verificationService: services.NewEmailVerificationService(ctx),
+		throttleService:     services.NewLoginThrottleService(ctx),
+		authService:         services.NewAuthService(ctx),
+		tokenService:        services.NewTokenService(ctx),
+		twoFactorService:    services.NewTwoFactorService(ctx),
+		passwordPolicy:      services.NewPasswordPolicyService(ctx),
+		auditService:        services.NewAuditService(ctx),
+		restaurantService:   services.NewRestaurantService(ctx),
+		ctx:                 ctx,
 	}
 }
 
@@ -32,6 +62,16 @@ func (ac *AuthController) RegisterUser(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
+	if !ac.ctx.Config.App.PublicRegistrationEnabled {
+		response := models.RegisterResponse{
+			Success: false,
+			Error:   "Public registration is disabled, ask a manager for an invitation",
+		}
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
 	var req models.RegisterRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -43,6 +83,7 @@ func (ac *AuthController) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader((http.StatusBadRequest))
 
 		json.NewEncoder(w).Encode(response)
+		return
 	}
 
 	if err := ac.validateRegisterRequest(&req); err != nil {
@@ -55,6 +96,11 @@ func (ac *AuthController) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if violations := ac.passwordPolicy.Validate(req.Password, req.Name, req.Email); len(violations) > 0 {
+		writePasswordViolations(w, violations)
+		return
+	}
+
 	exists, err := ac.userRepo.UserExists(req.Email)
 	if err != nil {
 		response := models.RegisterResponse{
@@ -91,6 +137,7 @@ func (ac *AuthController) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		Name:     strings.TrimSpace(req.Name),
 		Email:    strings.ToLower(strings.TrimSpace(req.Email)),
 		Password: hashedPassword,
+		Role:     ac.defaultRole(),
 	}
 
 	if err := ac.userRepo.CreateUser(user); err != nil {
@@ -103,20 +150,33 @@ func (ac *AuthController) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokenData := fmt.Sprintf("%s:%d:%s", user.Id.String(), time.Now().Unix(), ac.ctx.Config.App.CookieSecretKey)
-
-	hashedToken := utils.HashString(tokenData)
+	if err := ac.restaurantService.JoinDefaultRestaurant(user); err != nil {
+		log.Printf("ERROR: Failed to add %s to the default restaurant: %v", user.Id, err)
+	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "dashboard-cookie",
-		Value:    hashedToken,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteStrictMode,
-		MaxAge:   86400 * 7,
+	ac.auditService.Record(r, user, models.AuditActionUserRegistered, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"email": user.Email,
+		"name":  user.Name,
+		"role":  user.Role,
 	})
 
+	if err := ac.verificationService.SendVerification(user, user.Email); err != nil {
+		log.Printf("ERROR: Failed to send verification email: %v", err)
+	}
+
+	token, _, err := ac.sessionService.CreateSession(user.Id, r)
+	if err != nil {
+		response := models.RegisterResponse{
+			Success: false,
+			Error:   "Error creating session",
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	ac.sessionService.SetSessionCookie(w, token)
+
 	response := models.RegisterResponse{
 		Success: true,
 		Message: "User registered successfully",
@@ -147,6 +207,7 @@ func (ac *AuthController) LoginUser(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader((http.StatusBadRequest))
 
 		json.NewEncoder(w).Encode(response)
+		return
 	}
 
 	if err := ac.validateLoginRequest(&req); err != nil {
@@ -159,115 +220,1058 @@ func (ac *AuthController) LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	exists, err := ac.userRepo.UserExists(req.Email)
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	existedUser, ok := ac.authenticate(w, r, email, req.Password)
+	if !ok {
+		return
+	}
+
+	if existedUser.IsTwoFactorEnabled() {
+		ac.writeMFAChallenge(w, existedUser)
+		return
+	}
+
+	token, _, err := ac.sessionService.CreateSession(existedUser.Id, r)
 	if err != nil {
 		response := models.RegisterResponse{
 			Success: false,
-			Error:   "Database error",
+			Error:   "Error creating session",
 		}
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(response)
 		return
 	}
 
-	if !exists {
-		response := models.RegisterResponse{
+	ac.sessionService.SetSessionCookie(w, token)
+
+	response := models.RegisterResponse{
+		Success: true,
+		Message: "User login success",
+		User:    existedUser,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+
+}
+
+// LoginTwoFactor completes a login that was answered with an MFA
+// challenge and starts the dashboard session.
+func (ac *AuthController) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.LoginTwoFactorRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Error:   "User with this email not exists",
-		}
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	// Provider sign ins hand the challenge over in a cookie instead.
+	mfaToken := req.MFAToken
+	if cookie, err := r.Cookie(services.MFAChallengeCookieName); mfaToken == "" && err == nil {
+		mfaToken = cookie.Value
+	}
+
+	user, ok := ac.completeMFAChallenge(w, r, mfaToken, req.Code, req.RecoveryCode)
+	if !ok {
 		return
 	}
 
-	existedUser, err := ac.userRepo.GetUserByEmail(req.Email)
+	ac.twoFactorService.ClearChallengeCookie(w)
 
+	token, _, err := ac.sessionService.CreateSession(user.Id, r)
 	if err != nil {
-		response := models.RegisterResponse{
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
-			Error:   "Error processing existed user password",
-		}
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+			Error:   "Error creating session",
+		})
 		return
 	}
 
-	isEqualPass := utils.IsPasswordEqualHash(existedUser.Password, req.Password)
-	if !isEqualPass {
-		response := models.RegisterResponse{
+	ac.sessionService.SetSessionCookie(w, token)
+
+	utils.WriteJSON(w, http.StatusOK, models.LoginResponse{
+		Success: true,
+		Message: "User login success",
+		User:    user,
+	})
+}
+
+// Token issues bearer access and refresh tokens for clients that cannot
+// use the dashboard cookie, such as POS terminals and mobile apps.
+func (ac *AuthController) Token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TokenRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Error:   "Error invalid email or password",
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	switch req.GrantType {
+	case models.GrantTypePassword:
+		loginReq := models.LoginRequest{Email: req.Email, Password: req.Password}
+		if err := ac.validateLoginRequest(&loginReq); err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
 		}
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+
+		user, ok := ac.authenticate(w, r, strings.ToLower(strings.TrimSpace(req.Email)), req.Password)
+		if !ok {
+			return
+		}
+
+		if user.IsTwoFactorEnabled() {
+			ac.writeMFAChallenge(w, user)
+			return
+		}
+
+		ac.writeTokens(w, r, user)
+
+	case models.GrantTypeMFA:
+		user, ok := ac.completeMFAChallenge(w, r, req.MFAToken, req.Code, req.RecoveryCode)
+		if !ok {
+			return
+		}
+
+		ac.writeTokens(w, r, user)
+
+	case models.GrantTypeRefreshToken:
+		if strings.TrimSpace(req.RefreshToken) == "" {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "refresh_token is required",
+			})
+			return
+		}
+
+		response, err := ac.tokenService.Refresh(strings.TrimSpace(req.RefreshToken), r)
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
+			utils.WriteJSON(w, http.StatusUnauthorized, models.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error issuing tokens",
+			})
+			return
+		}
+
+		utils.WriteJSON(w, http.StatusOK, response)
+
+	default:
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "grant_type must be password, mfa or refresh_token",
+		})
+	}
+}
+
+func (ac *AuthController) writeTokens(w http.ResponseWriter, r *http.Request, user *models.User) {
+	response, err := ac.tokenService.IssueTokens(user.Id, r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error issuing tokens",
+		})
 		return
 	}
 
-	tokenData := fmt.Sprintf("%s:%d:%s", existedUser.Id.String(), time.Now().Unix(), ac.ctx.Config.App.CookieSecretKey)
+	response.User = user
+	utils.WriteJSON(w, http.StatusOK, response)
+}
 
-	hashedToken := utils.HashString(tokenData)
+func (ac *AuthController) writeMFAChallenge(w http.ResponseWriter, user *models.User) {
+	mfaToken, err := ac.twoFactorService.CreateChallenge(user.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating two-factor challenge",
+		})
+		return
+	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "dashboard-cookie",
-		Value:    hashedToken,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteStrictMode,
-		MaxAge:   86400 * 7,
+	utils.WriteJSON(w, http.StatusOK, models.MFAChallengeResponse{
+		Success:     true,
+		Message:     "Two-factor authentication code required",
+		MFARequired: true,
+		MFAToken:    mfaToken,
 	})
+}
 
-	response := models.RegisterResponse{
+// completeMFAChallenge checks the second factor for a pending login and
+// writes the error response when it is rejected.
+func (ac *AuthController) completeMFAChallenge(w http.ResponseWriter, r *http.Request, mfaToken, code, recoveryCode string) (*models.User, bool) {
+	user, err := ac.twoFactorService.ResolveChallenge(mfaToken)
+	if errors.Is(err, services.ErrInvalidMFAChallenge) {
+		utils.WriteJSON(w, http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	clientIP := utils.GetClientIP(r)
+
+	retryAfter, err := ac.throttleService.BeginAttempt(user.Email, clientIP)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		utils.WriteJSON(w, http.StatusTooManyRequests, models.ErrorResponse{
+			Success: false,
+			Error:   "Too many failed login attempts, please try again later",
+		})
+		return nil, false
+	}
+
+	ok, err := ac.twoFactorService.VerifyCodeOrRecoveryCode(user, code, recoveryCode)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	if !ok {
+		if err := ac.throttleService.RegisterFailure(user.Email, clientIP); err != nil {
+			log.Printf("ERROR: Failed to register login failure: %v", err)
+		}
+
+		ac.auditService.Record(r, nil, models.AuditActionLoginFailed, models.AuditTargetUser, user.Id.String(), map[string]any{
+			"email":  user.Email,
+			"reason": "invalid_mfa_code",
+		})
+
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid two-factor code",
+		})
+		return nil, false
+	}
+
+	if err := ac.twoFactorService.UseChallenge(mfaToken); err != nil {
+		if errors.Is(err, services.ErrInvalidMFAChallenge) {
+			utils.WriteJSON(w, http.StatusUnauthorized, models.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return nil, false
+		}
+
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	if err := ac.throttleService.RegisterSuccess(user.Email, clientIP); err != nil {
+		log.Printf("ERROR: Failed to reset login throttle: %v", err)
+	}
+
+	ac.auditService.Record(r, user, models.AuditActionLoginSucceeded, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"method": "mfa",
+	})
+
+	return user, true
+}
+
+func (ac *AuthController) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.RevokeTokenRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if strings.TrimSpace(req.RefreshToken) == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "refresh_token is required",
+		})
+		return
+	}
+
+	if _, err := ac.tokenService.Revoke(strings.TrimSpace(req.RefreshToken)); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error revoking token",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
 		Success: true,
-		Message: "User login success",
-		User:    existedUser,
+		Message: "Token revoked",
+	})
+}
+
+func (ac *AuthController) Me(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	response := models.UserResponse{
+		Success: true,
+		User:    middlewares.GetCurrentUser(r),
+	}
 
+	utils.WriteJSON(w, http.StatusOK, response)
 }
 
-func (ac *AuthController) validateRegisterRequest(req *models.RegisterRequest) error {
-	if strings.TrimSpace(req.Name) == "" {
-		return fmt.Errorf("name is required")
+func (ac *AuthController) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
-	if len(strings.TrimSpace(req.Name)) < 2 {
-		return fmt.Errorf("name must be at least 2 characters long")
+
+	// Bearer clients have no session here, they end their sign-in
+	// through /api/auth/token/revoke.
+	if session := middlewares.GetCurrentSession(r); session != nil {
+		if err := ac.sessionService.DeleteSession(session.Id); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error ending session",
+			})
+			return
+		}
 	}
-	if len(strings.TrimSpace(req.Name)) > 50 {
-		return fmt.Errorf("name must be no more than 50 characters long")
+
+	ac.sessionService.ClearSessionCookie(w)
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Logged out successfully",
+	})
+}
+
+func (ac *AuthController) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	if strings.TrimSpace(req.Email) == "" {
-		return fmt.Errorf("email is required")
+	user := middlewares.GetCurrentUser(r)
+
+	revoked, err := ac.sessionService.RevokeAllUserSessions(user.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error ending sessions",
+		})
+		return
 	}
-	if !strings.Contains(req.Email, "@") || !strings.Contains(req.Email, ".") {
-		return fmt.Errorf("invalid email format")
+
+	ac.sessionService.ClearSessionCookie(w)
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: fmt.Sprintf("Logged out from %d sessions", revoked),
+	})
+}
+
+func (ac *AuthController) ListSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	if strings.TrimSpace(req.Password) == "" {
-		return fmt.Errorf("password is required")
+	user := middlewares.GetCurrentUser(r)
+	current := middlewares.GetCurrentSession(r)
+
+	sessions, err := ac.sessionService.GetUserSessions(user.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
 	}
-	if len(strings.TrimSpace(req.Password)) < 6 {
-		return fmt.Errorf("password must be at least 6 characters long")
+
+	views := make([]*models.SessionView, 0, len(sessions))
+	for _, session := range sessions {
+		views = append(views, &models.SessionView{
+			Session: session,
+			Device:  utils.DescribeDevice(session.UserAgent),
+			Current: current != nil && session.Id == current.Id,
+		})
 	}
 
-	return nil
+	utils.WriteJSON(w, http.StatusOK, models.SessionsResponse{
+		Success:  true,
+		Sessions: views,
+	})
 }
 
-func (ac *AuthController) validateLoginRequest(req *models.LoginRequest) error {
-	if strings.TrimSpace(req.Email) == "" {
-		return fmt.Errorf("email is required")
+func (ac *AuthController) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
-	if !strings.Contains(req.Email, "@") || !strings.Contains(req.Email, ".") {
-		return fmt.Errorf("invalid email format")
+
+	sessionId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid session id",
+		})
+		return
 	}
-	if strings.TrimSpace(req.Password) == "" {
-		return fmt.Errorf("password is required")
+
+	user := middlewares.GetCurrentUser(r)
+
+	revoked, err := ac.sessionService.RevokeUserSession(sessionId, user.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error ending session",
+		})
+		return
+	}
+
+	if !revoked {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Session not found",
+		})
+		return
 	}
-	if len(strings.TrimSpace(req.Password)) < 6 {
-		return fmt.Errorf("password must be at least 6 characters long")
+
+	if current := middlewares.GetCurrentSession(r); current != nil && current.Id == sessionId {
+		ac.sessionService.ClearSessionCookie(w)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Session revoked",
+	})
+}
+
+func (ac *AuthController) Permissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	permissions, err := ac.roleRepo.GetPermissionsByRole(user.Role)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.PermissionsResponse{
+		Success:     true,
+		Role:        user.Role,
+		Permissions: permissions,
+	})
+}
+
+func (ac *AuthController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.VerifyEmailRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if strings.TrimSpace(req.Token) == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "token is required",
+		})
+		return
+	}
+
+	verified, err := ac.verificationService.Verify(strings.TrimSpace(req.Token))
+	if errors.Is(err, services.ErrEmailTaken) {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "User with this email already exists",
+		})
+		return
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if !verified {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Verification token is invalid or expired",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Email verified successfully",
+	})
+}
+
+func (ac *AuthController) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if user.IsEmailVerified() {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Email is already verified",
+		})
+		return
+	}
+
+	if err := ac.verificationService.SendVerification(user, user.Email); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error sending verification email",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Verification email sent",
+	})
+}
+
+// UnlockAccount lifts a login lockout for an email before it expires. The
+// email must belong to a member of the active restaurant.
+func (ac *AuthController) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UnlockAccountRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "email is required",
+		})
+		return
+	}
+
+	ip := ""
+	if value := strings.TrimSpace(req.IP); value != "" {
+		parsed := net.ParseIP(value)
+		if parsed == nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "invalid ip",
+			})
+			return
+		}
+		ip = parsed.String()
+	}
+
+	user, err := ac.userRepo.GetUserByEmail(email)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	var membership *models.RestaurantMembership
+	if user != nil {
+		membership, err = ac.restaurantRepo.GetUserRestaurant(user.Id, middlewares.GetCurrentRestaurant(r).Id)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+	}
+
+	if membership == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "No member of this restaurant uses this email",
+		})
+		return
+	}
+
+	unlocked, err := ac.throttleService.Unlock(email, ip)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if !unlocked {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Account is not locked",
+		})
+		return
+	}
+
+	log.Printf("SECURITY: Login for %q unlocked by %s", email, middlewares.GetCurrentUser(r).Email)
+
+	ac.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionUserUnlocked, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"email": email,
+		"ip":    ip,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Account unlocked",
+	})
+}
+
+// authenticate runs the password step of a staff login and records the
+// outcome in the audit log. Logins that still need a second factor are
+// recorded once the challenge is completed.
+func (ac *AuthController) authenticate(w http.ResponseWriter, r *http.Request, email, password string) (*models.User, bool) {
+	user, err := ac.authService.Authenticate(email, password, utils.GetClientIP(r))
+	return ac.checkAuthentication(w, r, email, user, err, "User with this email not exists")
+}
+
+// checkAuthentication writes the matching error response when the
+// credentials were rejected. Failures for an existing account name it as
+// the target, so they show in the audit log of its restaurants.
+func (ac *AuthController) checkAuthentication(w http.ResponseWriter, r *http.Request, login string, user *models.User, err error, unknownMessage string) (*models.User, bool) {
+	if err == nil {
+		if !user.IsTwoFactorEnabled() {
+			ac.auditService.Record(r, user, models.AuditActionLoginSucceeded, models.AuditTargetUser, user.Id.String(), map[string]any{
+				"method": "password",
+			})
+		}
+		return user, true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var throttled *services.LoginThrottledError
+
+	response := models.RegisterResponse{Success: false}
+	reason := ""
+
+	switch {
+	case errors.As(err, &throttled):
+		reason = "throttled"
+		response.Error = "Too many failed login attempts, please try again later"
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+	case errors.Is(err, services.ErrUserNotFound):
+		reason = "unknown_email"
+		response.Error = unknownMessage
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, services.ErrInvalidCredentials):
+		reason = "invalid_credentials"
+		response.Error = "Error invalid email or password"
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, services.ErrAccountDisabled):
+		reason = "account_disabled"
+		response.Error = "Account is deactivated, please contact your manager"
+		w.WriteHeader(http.StatusForbidden)
+	default:
+		response.Error = "Database error"
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if reason != "" {
+		details := map[string]any{"reason": reason}
+		if strings.Contains(login, "@") {
+			details["email"] = login
+		} else {
+			details["phone"] = login
+		}
+
+		targetId := ""
+		if user != nil {
+			targetId = user.Id.String()
+		}
+
+		ac.auditService.Record(r, nil, models.AuditActionLoginFailed, models.AuditTargetUser, targetId, details)
+	}
+
+	json.NewEncoder(w).Encode(response)
+	return nil, false
+}
+
+// RegisterCustomer signs up a customer for online ordering and
+// reservations. Unlike staff registration it is always open, and either an
+// email or a phone number is enough to sign in later.
+func (ac *AuthController) RegisterCustomer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.RegisterCustomerRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := ac.validateCustomerRequest(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if violations := ac.passwordPolicy.Validate(req.Password, req.Name, req.Email); len(violations) > 0 {
+		writePasswordViolations(w, violations)
+		return
+	}
+
+	if req.Email != "" {
+		exists, err := ac.userRepo.UserExists(req.Email)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+		if exists {
+			utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+				Success: false,
+				Error:   "User with this email already exists",
+			})
+			return
+		}
+	}
+
+	if req.Phone != "" {
+		exists, err := ac.userRepo.PhoneExists(req.Phone)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+		if exists {
+			utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+				Success: false,
+				Error:   "User with this phone number already exists",
+			})
+			return
+		}
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error processing password",
+		})
+		return
+	}
+
+	user := &models.User{
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		AccountType: models.AccountTypeCustomer,
+		Password:    hashedPassword,
+		Role:        models.RoleCustomer,
+	}
+
+	if err := ac.userRepo.CreateUser(user); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating user",
+		})
+		return
+	}
+
+	ac.auditService.Record(r, user, models.AuditActionCustomerRegistered, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"email": user.Email,
+		"phone": user.Phone,
+	})
+
+	if user.Email != "" {
+		if err := ac.verificationService.SendVerification(user, user.Email); err != nil {
+			log.Printf("ERROR: Failed to send verification email: %v", err)
+		}
+	}
+
+	ac.startCustomerSession(w, r, user, http.StatusCreated, "Customer registered successfully")
+}
+
+// LoginCustomer signs a customer in with their email or phone number.
+// Staff accounts are not found here, they sign in through LoginUser.
+func (ac *AuthController) LoginCustomer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CustomerLoginRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	login := strings.ToLower(strings.TrimSpace(req.Email))
+	if login == "" {
+		phone, ok := utils.NormalizePhone(req.Phone)
+		if !ok {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "email or phone is required",
+			})
+			return
+		}
+		login = phone
+	}
+
+	if req.Password == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "password is required",
+		})
+		return
+	}
+
+	user, err := ac.authService.AuthenticateCustomer(login, req.Password, utils.GetClientIP(r))
+	if _, ok := ac.checkAuthentication(w, r, login, user, err, "No customer account uses this email or phone"); !ok {
+		return
+	}
+
+	ac.startCustomerSession(w, r, user, http.StatusOK, "Customer login success")
+}
+
+// StartGuestSession lets someone order or book without an account. The
+// guest only gives a name and cannot sign in again once the session ends.
+func (ac *AuthController) StartGuestSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.GuestSessionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := validateName(name); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	retryAfter, err := ac.throttleService.BeginGuestSession(utils.GetClientIP(r))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		utils.WriteJSON(w, http.StatusTooManyRequests, models.ErrorResponse{
+			Success: false,
+			Error:   "Too many guest sessions, please try again later",
+		})
+		return
+	}
+
+	user := &models.User{
+		Name:        name,
+		AccountType: models.AccountTypeGuest,
+		Role:        models.RoleCustomer,
+	}
+
+	if err := ac.userRepo.CreateUser(user); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating guest",
+		})
+		return
+	}
+
+	ac.startCustomerSession(w, r, user, http.StatusCreated, "Guest session started")
+}
+
+func (ac *AuthController) startCustomerSession(w http.ResponseWriter, r *http.Request, user *models.User, status int, message string) {
+	token, _, err := ac.sessionService.CreateSession(user.Id, r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating session",
+		})
+		return
+	}
+
+	ac.sessionService.SetSessionCookie(w, token)
+
+	utils.WriteJSON(w, status, models.LoginResponse{
+		Success: true,
+		Message: message,
+		User:    user,
+	})
+}
+
+// defaultRole falls back to waiter, the configured role is checked at
+// startup.
+func (ac *AuthController) defaultRole() models.Role {
+	role, err := services.DefaultUserRole(ac.ctx.Config.App)
+	if err != nil {
+		return models.RoleWaiter
+	}
+	return role
+}
+
+func (ac *AuthController) validateRegisterRequest(req *models.RegisterRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(strings.TrimSpace(req.Name)) < 2 {
+		return fmt.Errorf("name must be at least 2 characters long")
+	}
+	if len(strings.TrimSpace(req.Name)) > 50 {
+		return fmt.Errorf("name must be no more than 50 characters long")
+	}
+
+	if strings.TrimSpace(req.Email) == "" {
+		return fmt.Errorf("email is required")
+	}
+	if !strings.Contains(req.Email, "@") || !strings.Contains(req.Email, ".") {
+		return fmt.Errorf("invalid email format")
+	}
+
+	return nil
+}
+
+// validateCustomerRequest also normalizes the email and phone number.
+func (ac *AuthController) validateCustomerRequest(req *models.RegisterCustomerRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validateName(req.Name); err != nil {
+		return err
+	}
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if req.Email != "" && (!strings.Contains(req.Email, "@") || !strings.Contains(req.Email, ".")) {
+		return fmt.Errorf("invalid email format")
+	}
+
+	if strings.TrimSpace(req.Phone) != "" {
+		phone, ok := utils.NormalizePhone(req.Phone)
+		if !ok {
+			return fmt.Errorf("invalid phone number, use the international format such as +4915112345678")
+		}
+		req.Phone = phone
+	}
+
+	if req.Email == "" && req.Phone == "" {
+		return fmt.Errorf("email or phone is required")
+	}
+
+	return nil
+}
+
+func (ac *AuthController) validateLoginRequest(req *models.LoginRequest) error {
+	if strings.TrimSpace(req.Email) == "" {
+		return fmt.Errorf("email is required")
+	}
+	if !strings.Contains(req.Email, "@") || !strings.Contains(req.Email, ".") {
+		return fmt.Errorf("invalid email format")
+	}
+	if req.Password == "" {
+		return fmt.Errorf("password is required")
 	}
 
 	return nil
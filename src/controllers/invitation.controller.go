@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"restaurant-backend/src/mailer"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InvitationController struct {
+	userRepo       *repositories.UserRepository
+	invitationRepo *repositories.InvitationRepository
+	roleRepo       *repositories.RoleRepository
+	sessionService *services.SessionService
+	passwordPolicy *services.PasswordPolicyService
+	auditService   *services.AuditService
+	mailer         mailer.Mailer
+	ctx            *models.AppContext
+}
+
+func NewInvitationController(ctx *models.AppContext) *InvitationController {
+	return &InvitationController{
+		userRepo:       repositories.NewUserRepository(ctx.DB),
+		invitationRepo: repositories.NewInvitationRepository(ctx.DB),
+		roleRepo:       repositories.NewRoleRepository(ctx.DB),
+		sessionService: services.NewSessionService(ctx),
+		passwordPolicy: services.NewPasswordPolicyService(ctx),
+		auditService:   services.NewAuditService(ctx),
+		mailer:         mailer.NewMailer(ctx.Config.Mail),
+		ctx:            ctx,
+	}
+}
+
+func (ic *InvitationController) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateInvitationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" || !strings.Contains(email, "@") || !strings.Contains(email, ".") {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "invalid email format",
+		})
+		return
+	}
+
+	if !models.IsValidRole(req.Role) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "invalid role",
+		})
+		return
+	}
+
+	inviter := middlewares.GetCurrentUser(r)
+	restaurant := middlewares.GetCurrentRestaurant(r)
+
+	if !canGrantRole(w, ic.roleRepo, inviter, models.Role(req.Role)) {
+		return
+	}
+
+	// Invitations go out whether or not the address has an account, people
+	// who have one accept while signed in, see JoinInvitation.
+	if err := ic.invitationRepo.RevokePendingInvitationsByEmail(restaurant.Id, email); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	token := utils.GenerateRandomToken()
+	ttl := time.Duration(ic.ctx.Config.App.InvitationTTLHours) * time.Hour
+
+	invitation := &models.Invitation{
+		RestaurantId: restaurant.Id,
+		Email:        email,
+		Role:         models.Role(req.Role),
+		TokenHash:    utils.HashString(token),
+		InvitedBy:    &inviter.Id,
+		ExpiresAt:    time.Now().Add(ttl),
+	}
+
+	if err := ic.invitationRepo.CreateInvitation(invitation); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating invitation",
+		})
+		return
+	}
+
+	ic.auditService.Record(r, inviter, models.AuditActionInvitationCreated, models.AuditTargetInvitation, invitation.Id.String(), map[string]any{
+		"email": invitation.Email,
+		"role":  invitation.Role,
+	})
+
+	link := fmt.Sprintf("%s/accept-invitation?token=%s", strings.TrimRight(ic.ctx.Config.App.FrontendURL, "/"), token)
+
+	err := ic.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "You have been invited to join the team",
+		Body: fmt.Sprintf("Hello,\n\n%s has invited you to join %s as %s. Open the link below to accept, signing in if you already have an account. It expires in %d hours.\n\n%s",
+			inviter.Name, restaurant.Name, invitation.Role, ic.ctx.Config.App.InvitationTTLHours, link),
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to send invitation email: %v", err)
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, models.InvitationResponse{
+		Success:    true,
+		Message:    "Invitation sent",
+		Invitation: invitation,
+	})
+}
+
+func (ic *InvitationController) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	invitations, err := ic.invitationRepo.GetPendingInvitations(middlewares.GetCurrentRestaurant(r).Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.InvitationsResponse{
+		Success:     true,
+		Invitations: invitations,
+	})
+}
+
+func (ic *InvitationController) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	invitationId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid invitation id",
+		})
+		return
+	}
+
+	revoked, err := ic.invitationRepo.RevokeInvitation(middlewares.GetCurrentRestaurant(r).Id, invitationId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if !revoked {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Invitation not found",
+		})
+		return
+	}
+
+	ic.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionInvitationRevoked, models.AuditTargetInvitation, invitationId.String(), nil)
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Invitation revoked",
+	})
+}
+
+// AcceptInvitation creates the invited account as a member of the
+// restaurant it was invited to. The invitation link proves the address, so
+// the email counts as verified. Existing accounts use JoinInvitation.
+func (ic *InvitationController) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.AcceptInvitationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := ic.validateAcceptRequest(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	invitation, err := ic.invitationRepo.GetPendingInvitationByTokenHash(utils.HashString(strings.TrimSpace(req.Token)))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if invitation == nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invitation is invalid or expired",
+		})
+		return
+	}
+
+	if violations := ic.passwordPolicy.Validate(req.Password, req.Name, invitation.Email); len(violations) > 0 {
+		writePasswordViolations(w, violations)
+		return
+	}
+
+	exists, err := ic.userRepo.UserExists(invitation.Email)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if exists {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "An account already uses this email, sign in to accept the invitation",
+		})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error processing password",
+		})
+		return
+	}
+
+	verifiedAt := time.Now()
+	user := &models.User{
+		Name:            strings.TrimSpace(req.Name),
+		Email:           invitation.Email,
+		Password:        hashedPassword,
+		Role:            invitation.Role,
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	accepted, err := ic.invitationRepo.Accept(invitation, user)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating user",
+		})
+		return
+	}
+
+	if !accepted {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invitation is invalid or expired",
+		})
+		return
+	}
+
+	ic.auditService.Record(r, user, models.AuditActionInvitationAccepted, models.AuditTargetInvitation, invitation.Id.String(), map[string]any{
+		"user_id": user.Id,
+		"role":    user.Role,
+	})
+
+	token, _, err := ic.sessionService.CreateSession(user.Id, r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating session",
+		})
+		return
+	}
+
+	ic.sessionService.SetSessionCookie(w, token)
+
+	utils.WriteJSON(w, http.StatusCreated, models.RegisterResponse{
+		Success: true,
+		Message: "Invitation accepted",
+		User:    user,
+	})
+}
+
+// JoinInvitation accepts an invitation with the signed in account, which
+// must be the one the invitation was sent to. Accounts only join another
+// restaurant this way, with the consent of their owner.
+func (ic *InvitationController) JoinInvitation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.JoinInvitationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if strings.TrimSpace(req.Token) == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "token is required",
+		})
+		return
+	}
+
+	invitation, err := ic.invitationRepo.GetPendingInvitationByTokenHash(utils.HashString(strings.TrimSpace(req.Token)))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if invitation == nil || !strings.EqualFold(invitation.Email, user.Email) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invitation is invalid or expired",
+		})
+		return
+	}
+
+	if !user.IsStaff() {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Customer accounts cannot join a restaurant's staff",
+		})
+		return
+	}
+
+	accepted, err := ic.invitationRepo.Join(invitation, user)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error joining restaurant",
+		})
+		return
+	}
+
+	if !accepted {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invitation is invalid or expired",
+		})
+		return
+	}
+
+	ic.auditService.Record(r, user, models.AuditActionInvitationAccepted, models.AuditTargetInvitation, invitation.Id.String(), map[string]any{
+		"user_id": user.Id,
+		"role":    invitation.Role,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Invitation accepted",
+	})
+}
+
+func (ic *InvitationController) validateAcceptRequest(req *models.AcceptInvitationRequest) error {
+	if strings.TrimSpace(req.Token) == "" {
+		return fmt.Errorf("token is required")
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(strings.TrimSpace(req.Name)) < 2 {
+		return fmt.Errorf("name must be at least 2 characters long")
+	}
+	if len(strings.TrimSpace(req.Name)) > 50 {
+		return fmt.Errorf("name must be no more than 50 characters long")
+	}
+
+	return nil
+}
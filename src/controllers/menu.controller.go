@@ -0,0 +1,1026 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxMenuPrice caps prices at 1,000,000.00 in a two decimal currency.
+const maxMenuPrice = 100_000_000
+
+type MenuController struct {
+	menuRepo           *repositories.MenuRepository
+	restaurantRepo     *repositories.RestaurantRepository
+	menuService        *services.MenuService
+	translationService *services.TranslationService
+	auditService       *services.AuditService
+	ctx                *models.AppContext
+}
+
+func NewMenuController(ctx *models.AppContext) *MenuController {
+	return &MenuController{
+		menuRepo:           repositories.NewMenuRepository(ctx.DB),
+		restaurantRepo:     repositories.NewRestaurantRepository(ctx.DB),
+		menuService:        services.NewMenuService(ctx),
+		translationService: services.NewTranslationService(ctx),
+		auditService:       services.NewAuditService(ctx),
+		ctx:                ctx,
+	}
+}
+
+// GetMenu returns the whole menu of the active restaurant. It takes the
+// same filters as ListItems, see parseMenuItemFilter.
+func (mc *MenuController) GetMenu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := parseMenuItemFilter(r, middlewares.GetCurrentRestaurant(r).Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	categories, err := mc.menuService.GetMenu(filter)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuResponse{
+		Success:    true,
+		Categories: categories,
+	})
+}
+
+// GetPublicMenu is the menu guests see, without signing in. Only what can
+// be ordered now, or at ?at=, is included. The allergen and dietary filters
+// work as for staff, and texts are translated for Accept-Language.
+func (mc *MenuController) GetPublicMenu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	restaurantId, err := uuid.Parse(r.PathValue("restaurantId"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid restaurant id",
+		})
+		return
+	}
+
+	filter, err := parseMenuItemFilter(r, restaurantId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	restaurant, err := mc.restaurantRepo.GetRestaurant(restaurantId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if restaurant == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Restaurant not found",
+		})
+		return
+	}
+
+	at, err := parseAtParam(r.URL.Query().Get("at"), restaurant)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid at, use RFC 3339 or 2006-01-02T15:04",
+		})
+		return
+	}
+
+	openMenus, categories, err := mc.menuService.GetOrderableMenu(restaurant, filter, at)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if err := mc.translationService.TranslateMenu(restaurant.Id, utils.GetLocales(r), categories, openMenus); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+	w.Header().Set("Vary", "Accept-Language")
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuResponse{
+		Success:    true,
+		Restaurant: restaurant,
+		OpenMenus:  openMenus,
+		Categories: categories,
+	})
+}
+
+func (mc *MenuController) ListCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	categories, err := mc.menuRepo.GetCategories(middlewares.GetCurrentRestaurant(r).Id, false)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuResponse{
+		Success:    true,
+		Categories: categories,
+	})
+}
+
+func (mc *MenuController) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateMenuCategoryRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	category := &models.MenuCategory{
+		RestaurantId: middlewares.GetCurrentRestaurant(r).Id,
+		Name:         strings.TrimSpace(req.Name),
+		Description:  strings.TrimSpace(req.Description),
+		IsAvailable:  req.IsAvailable == nil || *req.IsAvailable,
+	}
+
+	if err := validateMenuEntry(category.Name, category.Description, req.Position); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := mc.menuRepo.CreateCategory(category, req.Position); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating category",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuCategoryCreated, models.AuditTargetMenuCategory, category.Id.String(), map[string]any{
+		"name": category.Name,
+	})
+
+	utils.WriteJSON(w, http.StatusCreated, models.MenuCategoryResponse{
+		Success:  true,
+		Category: category,
+	})
+}
+
+// GetCategory returns the category with all of its items.
+func (mc *MenuController) GetCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	category, ok := mc.loadCategory(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := mc.menuRepo.GetItems(models.MenuItemFilter{RestaurantId: category.RestaurantId, CategoryId: &category.Id})
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+	category.Items = items
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuCategoryResponse{
+		Success:  true,
+		Category: category,
+	})
+}
+
+func (mc *MenuController) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateMenuCategoryRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	category, ok := mc.loadCategory(w, r)
+	if !ok {
+		return
+	}
+
+	diff := models.AuditDiff{}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != category.Name {
+			diff["name"] = models.AuditChange{Old: category.Name, New: name}
+		}
+		category.Name = name
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if description != category.Description {
+			diff["description"] = models.AuditChange{Old: category.Description, New: description}
+		}
+		category.Description = description
+	}
+	if req.Position != nil && *req.Position != category.Position {
+		diff["position"] = models.AuditChange{Old: category.Position, New: *req.Position}
+		category.Position = *req.Position
+	}
+	if req.IsAvailable != nil && *req.IsAvailable != category.IsAvailable {
+		diff["is_available"] = models.AuditChange{Old: category.IsAvailable, New: *req.IsAvailable}
+		category.IsAvailable = *req.IsAvailable
+	}
+
+	if err := validateMenuEntry(category.Name, category.Description, &category.Position); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(diff) > 0 {
+		if err := mc.menuRepo.UpdateCategory(category); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error updating category",
+			})
+			return
+		}
+
+		mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuCategoryUpdated, models.AuditTargetMenuCategory, category.Id.String(), diff)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuCategoryResponse{
+		Success:  true,
+		Category: category,
+	})
+}
+
+// DeleteCategory only removes empty categories, so items are never lost by
+// accident. Move or delete the items first.
+func (mc *MenuController) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	category, ok := mc.loadCategory(w, r)
+	if !ok {
+		return
+	}
+
+	hasItems, err := mc.menuRepo.CategoryHasItems(category.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if hasItems {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Category still has items",
+		})
+		return
+	}
+
+	deleted, err := mc.menuRepo.DeleteCategory(category.RestaurantId, category.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error deleting category",
+		})
+		return
+	}
+
+	if !deleted {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Category not found",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuCategoryDeleted, models.AuditTargetMenuCategory, category.Id.String(), map[string]any{
+		"name": category.Name,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Category deleted",
+	})
+}
+
+// ReorderCategories takes every category id of the restaurant in the new
+// order.
+func (mc *MenuController) ReorderCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, ok := decodeReorderRequest(w, r)
+	if !ok {
+		return
+	}
+
+	restaurantId := middlewares.GetCurrentRestaurant(r).Id
+
+	reordered, err := mc.menuRepo.ReorderCategories(restaurantId, req.Ids)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error reordering categories",
+		})
+		return
+	}
+
+	if !reordered {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "ids must be distinct categories of this restaurant",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuReordered, models.AuditTargetRestaurant, restaurantId.String(), map[string]any{
+		"category_ids": req.Ids,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Categories reordered",
+	})
+}
+
+// ReorderItems takes item ids of one category in the new order.
+func (mc *MenuController) ReorderItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, ok := decodeReorderRequest(w, r)
+	if !ok {
+		return
+	}
+
+	category, ok := mc.loadCategory(w, r)
+	if !ok {
+		return
+	}
+
+	reordered, err := mc.menuRepo.ReorderItems(category.RestaurantId, category.Id, req.Ids)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error reordering items",
+		})
+		return
+	}
+
+	if !reordered {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "ids must be distinct items of this category",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuReordered, models.AuditTargetMenuCategory, category.Id.String(), map[string]any{
+		"item_ids": req.Ids,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Items reordered",
+	})
+}
+
+// ListAllergens returns the allergen and dietary tag codes items can carry.
+func (mc *MenuController) ListAllergens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.AllergensResponse{
+		Success:     true,
+		Allergens:   models.AllAllergens,
+		DietaryTags: models.AllDietaryTags,
+	})
+}
+
+// ListItems takes the filters described at parseMenuItemFilter.
+func (mc *MenuController) ListItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := parseMenuItemFilter(r, middlewares.GetCurrentRestaurant(r).Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	items, err := mc.menuService.GetItems(filter)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuItemsResponse{
+		Success: true,
+		Items:   items,
+	})
+}
+
+func (mc *MenuController) CreateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateMenuItemRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	item := &models.MenuItem{
+		RestaurantId:      middlewares.GetCurrentRestaurant(r).Id,
+		CategoryId:        req.CategoryId,
+		Name:              strings.TrimSpace(req.Name),
+		Description:       strings.TrimSpace(req.Description),
+		Allergens:         normalizeTags(req.Allergens, models.AllAllergens),
+		AllergensDeclared: req.Allergens != nil,
+		DietaryTags:       normalizeTags(req.DietaryTags, models.AllDietaryTags),
+		IsAvailable:       req.IsAvailable == nil || *req.IsAvailable,
+	}
+
+	if err := validateTags(req.Allergens, req.DietaryTags); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.Price == nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "price is required",
+		})
+		return
+	}
+	item.Price = *req.Price
+
+	if err := validateMenuItem(item, req.Position); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if !mc.checkCategory(w, item.RestaurantId, item.CategoryId) {
+		return
+	}
+
+	if err := mc.menuRepo.CreateItem(item, req.Position); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating item",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuItemCreated, models.AuditTargetMenuItem, item.Id.String(), map[string]any{
+		"name":               item.Name,
+		"category_id":        item.CategoryId,
+		"price":              item.Price,
+		"allergens":          item.Allergens,
+		"allergens_declared": item.AllergensDeclared,
+		"dietary_tags":       item.DietaryTags,
+	})
+
+	utils.WriteJSON(w, http.StatusCreated, models.MenuItemResponse{
+		Success: true,
+		Item:    item,
+	})
+}
+
+func (mc *MenuController) GetItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	item, ok := mc.loadItem(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuItemResponse{
+		Success: true,
+		Item:    item,
+	})
+}
+
+func (mc *MenuController) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateMenuItemRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	item, ok := mc.loadItem(w, r)
+	if !ok {
+		return
+	}
+
+	diff := models.AuditDiff{}
+
+	if req.CategoryId != nil && *req.CategoryId != item.CategoryId {
+		if !mc.checkCategory(w, item.RestaurantId, *req.CategoryId) {
+			return
+		}
+		diff["category_id"] = models.AuditChange{Old: item.CategoryId, New: *req.CategoryId}
+		item.CategoryId = *req.CategoryId
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != item.Name {
+			diff["name"] = models.AuditChange{Old: item.Name, New: name}
+		}
+		item.Name = name
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if description != item.Description {
+			diff["description"] = models.AuditChange{Old: item.Description, New: description}
+		}
+		item.Description = description
+	}
+	if req.Price != nil && *req.Price != item.Price {
+		diff["price"] = models.AuditChange{Old: item.Price, New: *req.Price}
+		item.Price = *req.Price
+	}
+	if req.Position != nil && *req.Position != item.Position {
+		diff["position"] = models.AuditChange{Old: item.Position, New: *req.Position}
+		item.Position = *req.Position
+	}
+	if req.IsAvailable != nil && *req.IsAvailable != item.IsAvailable {
+		diff["is_available"] = models.AuditChange{Old: item.IsAvailable, New: *req.IsAvailable}
+		item.IsAvailable = *req.IsAvailable
+	}
+
+	// Allergen changes get their own audit entry so the declaration
+	// history of an item can be looked up on its own.
+	allergenDiff := models.AuditDiff{}
+
+	if req.Allergens != nil {
+		if err := validateTags(*req.Allergens, nil); err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		allergens := normalizeTags(*req.Allergens, models.AllAllergens)
+		if !slices.Equal(allergens, item.Allergens) {
+			allergenDiff["allergens"] = models.AuditChange{Old: item.Allergens, New: allergens}
+		}
+		if !item.AllergensDeclared {
+			allergenDiff["allergens_declared"] = models.AuditChange{Old: false, New: true}
+		}
+		item.Allergens = allergens
+		item.AllergensDeclared = true
+	}
+	if req.DietaryTags != nil {
+		if err := validateTags(nil, *req.DietaryTags); err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		dietaryTags := normalizeTags(*req.DietaryTags, models.AllDietaryTags)
+		if !slices.Equal(dietaryTags, item.DietaryTags) {
+			allergenDiff["dietary_tags"] = models.AuditChange{Old: item.DietaryTags, New: dietaryTags}
+		}
+		item.DietaryTags = dietaryTags
+	}
+
+	if err := validateMenuItem(item, &item.Position); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(diff) > 0 || len(allergenDiff) > 0 {
+		if err := mc.menuRepo.UpdateItem(item); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error updating item",
+			})
+			return
+		}
+	}
+
+	if len(diff) > 0 {
+		mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuItemUpdated, models.AuditTargetMenuItem, item.Id.String(), diff)
+	}
+	if len(allergenDiff) > 0 {
+		mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuItemAllergensChanged, models.AuditTargetMenuItem, item.Id.String(), allergenDiff)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuItemResponse{
+		Success: true,
+		Item:    item,
+	})
+}
+
+func (mc *MenuController) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	item, ok := mc.loadItem(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := mc.menuRepo.DeleteItem(item.RestaurantId, item.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error deleting item",
+		})
+		return
+	}
+
+	if !deleted {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Item not found",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuItemDeleted, models.AuditTargetMenuItem, item.Id.String(), map[string]any{
+		"name": item.Name,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Item deleted",
+	})
+}
+
+func (mc *MenuController) loadCategory(w http.ResponseWriter, r *http.Request) (*models.MenuCategory, bool) {
+	categoryId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid category id",
+		})
+		return nil, false
+	}
+
+	category, err := mc.menuRepo.GetCategory(middlewares.GetCurrentRestaurant(r).Id, categoryId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	if category == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Category not found",
+		})
+		return nil, false
+	}
+
+	return category, true
+}
+
+// loadItem includes the item's modifier groups.
+func (mc *MenuController) loadItem(w http.ResponseWriter, r *http.Request) (*models.MenuItem, bool) {
+	itemId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid item id",
+		})
+		return nil, false
+	}
+
+	item, err := mc.menuService.GetItem(middlewares.GetCurrentRestaurant(r).Id, itemId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	if item == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Item not found",
+		})
+		return nil, false
+	}
+
+	return item, true
+}
+
+// checkCategory makes sure items are only put into categories of their own
+// restaurant.
+func (mc *MenuController) checkCategory(w http.ResponseWriter, restaurantId, categoryId uuid.UUID) bool {
+	category, err := mc.menuRepo.GetCategory(restaurantId, categoryId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return false
+	}
+
+	if category == nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "category_id is not a category of this restaurant",
+		})
+		return false
+	}
+
+	return true
+}
+
+func decodeReorderRequest(w http.ResponseWriter, r *http.Request) (*models.ReorderMenuRequest, bool) {
+	var req models.ReorderMenuRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return nil, false
+	}
+
+	if len(req.Ids) == 0 {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "ids is required",
+		})
+		return nil, false
+	}
+
+	return &req, true
+}
+
+func validateMenuEntry(name, description string, position *int) error {
+	if err := validateRestaurantName("name", name); err != nil {
+		return err
+	}
+	if len(description) > 1000 {
+		return fmt.Errorf("description must be no more than 1000 characters long")
+	}
+	if position != nil && *position < 0 {
+		return fmt.Errorf("position must not be negative")
+	}
+
+	return nil
+}
+
+func validateMenuItem(item *models.MenuItem, position *int) error {
+	if err := validateMenuEntry(item.Name, item.Description, position); err != nil {
+		return err
+	}
+	if item.CategoryId == uuid.Nil {
+		return fmt.Errorf("category_id is required")
+	}
+	if item.Price < 0 {
+		return fmt.Errorf("price must not be negative")
+	}
+	if item.Price > maxMenuPrice {
+		return fmt.Errorf("price must be no more than %d", maxMenuPrice)
+	}
+
+	return validateDiet(item.Allergens, item.DietaryTags)
+}
+
+// dietConflicts lists the allergens a dietary tag rules out.
+var dietConflicts = map[string][]string{
+	models.DietaryTagVegan: {
+		models.AllergenMilk, models.AllergenEggs, models.AllergenFish, models.AllergenCrustaceans, models.AllergenMolluscs,
+	},
+	models.DietaryTagVegetarian: {
+		models.AllergenFish, models.AllergenCrustaceans, models.AllergenMolluscs,
+	},
+	models.DietaryTagGlutenFree: {
+		models.AllergenGluten,
+	},
+}
+
+// validateDiet rejects dietary tags that contradict the declared
+// allergens, such as a vegan dish containing milk.
+func validateDiet(allergens, dietaryTags []string) error {
+	for _, tag := range dietaryTags {
+		for _, allergen := range dietConflicts[tag] {
+			if slices.Contains(allergens, allergen) {
+				return fmt.Errorf("an item containing %s cannot be tagged %s", allergen, tag)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateTags(allergens, dietaryTags []string) error {
+	for _, allergen := range allergens {
+		if !slices.Contains(models.AllAllergens, strings.TrimSpace(allergen)) {
+			return fmt.Errorf("unknown allergen %q", allergen)
+		}
+	}
+	for _, tag := range dietaryTags {
+		if !slices.Contains(models.AllDietaryTags, strings.TrimSpace(tag)) {
+			return fmt.Errorf("unknown dietary tag %q", tag)
+		}
+	}
+
+	return nil
+}
+
+// normalizeTags drops duplicates and sorts the values in the order of
+// known, so equal sets compare equal. Unknown values are dropped, validate
+// them first.
+func normalizeTags(values, known []string) []string {
+	normalized := []string{}
+
+	for _, tag := range known {
+		for _, value := range values {
+			if strings.TrimSpace(value) == tag {
+				normalized = append(normalized, tag)
+				break
+			}
+		}
+	}
+
+	return normalized
+}
+
+// parseMenuItemFilter reads category_id, available, and comma separated
+// lists of allergens to exclude and dietary tags to require, as in
+// ?exclude_allergens=nuts,peanuts&dietary=vegan.
+func parseMenuItemFilter(r *http.Request, restaurantId uuid.UUID) (models.MenuItemFilter, error) {
+	query := r.URL.Query()
+
+	filter := models.MenuItemFilter{RestaurantId: restaurantId}
+
+	if value := query.Get("category_id"); value != "" {
+		categoryId, err := uuid.Parse(value)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid category_id")
+		}
+		filter.CategoryId = &categoryId
+	}
+
+	var err error
+	if filter.IsAvailable, err = parseBoolParam(query.Get("available")); err != nil {
+		return filter, fmt.Errorf("Invalid available, use true or false")
+	}
+
+	excluded := splitListParam(query["exclude_allergens"])
+	dietary := splitListParam(query["dietary"])
+
+	if err := validateTags(excluded, dietary); err != nil {
+		return filter, err
+	}
+
+	filter.ExcludeAllergens = normalizeTags(excluded, models.AllAllergens)
+	filter.DietaryTags = normalizeTags(dietary, models.AllDietaryTags)
+
+	return filter, nil
+}
+
+func splitListParam(values []string) []string {
+	list := []string{}
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				list = append(list, part)
+			}
+		}
+	}
+
+	return list
+}
+
+func parseBoolParam(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
@@ -0,0 +1,750 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ModifierController struct {
+	modifierRepo       *repositories.ModifierRepository
+	menuService        *services.MenuService
+	translationService *services.TranslationService
+	auditService       *services.AuditService
+	ctx                *models.AppContext
+}
+
+func NewModifierController(ctx *models.AppContext) *ModifierController {
+	return &ModifierController{
+		modifierRepo:       repositories.NewModifierRepository(ctx.DB),
+		menuService:        services.NewMenuService(ctx),
+		translationService: services.NewTranslationService(ctx),
+		auditService:       services.NewAuditService(ctx),
+		ctx:                ctx,
+	}
+}
+
+func (mc *ModifierController) ListGroups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groups, err := mc.modifierRepo.GetGroups(middlewares.GetCurrentRestaurant(r).Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.ModifierGroupsResponse{
+		Success:        true,
+		ModifierGroups: groups,
+	})
+}
+
+// CreateGroup can create the first options together with the group.
+func (mc *ModifierController) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateModifierGroupRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	group := &models.ModifierGroup{
+		RestaurantId:  middlewares.GetCurrentRestaurant(r).Id,
+		Name:          strings.TrimSpace(req.Name),
+		IsRequired:    req.IsRequired,
+		MinSelections: req.MinSelections,
+		MaxSelections: req.MaxSelections,
+		Options:       []*models.ModifierOption{},
+	}
+
+	if err := validateModifierGroup(group); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	for i, optionReq := range req.Options {
+		option := &models.ModifierOption{
+			Name:              strings.TrimSpace(optionReq.Name),
+			PriceDelta:        optionReq.PriceDelta,
+			Allergens:         normalizeTags(optionReq.Allergens, models.AllAllergens),
+			AllergensDeclared: optionReq.Allergens != nil,
+			Position:          i,
+			IsAvailable:       optionReq.IsAvailable == nil || *optionReq.IsAvailable,
+		}
+		if optionReq.Position != nil {
+			option.Position = *optionReq.Position
+		}
+
+		err := validateTags(optionReq.Allergens, nil)
+		if err == nil {
+			err = validateModifierOption(option)
+		}
+		if err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   fmt.Sprintf("options[%d]: %v", i, err),
+			})
+			return
+		}
+
+		group.Options = append(group.Options, option)
+	}
+
+	if err := mc.modifierRepo.CreateGroup(group); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating modifier group",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionModifierGroupCreated, models.AuditTargetModifierGroup, group.Id.String(), map[string]any{
+		"name": group.Name,
+	})
+
+	utils.WriteJSON(w, http.StatusCreated, models.ModifierGroupResponse{
+		Success:       true,
+		ModifierGroup: group,
+	})
+}
+
+func (mc *ModifierController) GetGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	group, ok := mc.loadGroup(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.ModifierGroupResponse{
+		Success:       true,
+		ModifierGroup: group,
+	})
+}
+
+func (mc *ModifierController) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateModifierGroupRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	group, ok := mc.loadGroup(w, r)
+	if !ok {
+		return
+	}
+
+	diff := models.AuditDiff{}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != group.Name {
+			diff["name"] = models.AuditChange{Old: group.Name, New: name}
+		}
+		group.Name = name
+	}
+	if req.IsRequired != nil && *req.IsRequired != group.IsRequired {
+		diff["is_required"] = models.AuditChange{Old: group.IsRequired, New: *req.IsRequired}
+		group.IsRequired = *req.IsRequired
+	}
+	if req.MinSelections != nil && *req.MinSelections != group.MinSelections {
+		diff["min_selections"] = models.AuditChange{Old: group.MinSelections, New: *req.MinSelections}
+		group.MinSelections = *req.MinSelections
+	}
+	if req.MaxSelections != nil {
+		var maxSelections *int
+		if *req.MaxSelections != 0 {
+			maxSelections = req.MaxSelections
+		}
+		if !equalIntPointers(maxSelections, group.MaxSelections) {
+			diff["max_selections"] = models.AuditChange{Old: group.MaxSelections, New: maxSelections}
+		}
+		group.MaxSelections = maxSelections
+	}
+
+	if err := validateModifierGroup(group); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(diff) > 0 {
+		if err := mc.modifierRepo.UpdateGroup(group); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error updating modifier group",
+			})
+			return
+		}
+
+		mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionModifierGroupUpdated, models.AuditTargetModifierGroup, group.Id.String(), diff)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.ModifierGroupResponse{
+		Success:       true,
+		ModifierGroup: group,
+	})
+}
+
+// DeleteGroup removes the group from every item it was attached to.
+func (mc *ModifierController) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	group, ok := mc.loadGroup(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := mc.modifierRepo.DeleteGroup(group.RestaurantId, group.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error deleting modifier group",
+		})
+		return
+	}
+
+	if !deleted {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Modifier group not found",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionModifierGroupDeleted, models.AuditTargetModifierGroup, group.Id.String(), map[string]any{
+		"name": group.Name,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Modifier group deleted",
+	})
+}
+
+func (mc *ModifierController) CreateOption(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateModifierOptionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	group, ok := mc.loadGroup(w, r)
+	if !ok {
+		return
+	}
+
+	option := &models.ModifierOption{
+		GroupId:           group.Id,
+		Name:              strings.TrimSpace(req.Name),
+		PriceDelta:        req.PriceDelta,
+		Allergens:         normalizeTags(req.Allergens, models.AllAllergens),
+		AllergensDeclared: req.Allergens != nil,
+		IsAvailable:       req.IsAvailable == nil || *req.IsAvailable,
+	}
+	if req.Position != nil {
+		option.Position = *req.Position
+	}
+
+	if err := validateTags(req.Allergens, nil); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := validateModifierOption(option); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := mc.modifierRepo.CreateOption(option, req.Position); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating option",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionModifierOptionCreated, models.AuditTargetModifierOption, option.Id.String(), map[string]any{
+		"group_id":           group.Id,
+		"name":               option.Name,
+		"price_delta":        option.PriceDelta,
+		"allergens":          option.Allergens,
+		"allergens_declared": option.AllergensDeclared,
+	})
+
+	utils.WriteJSON(w, http.StatusCreated, models.ModifierOptionResponse{
+		Success: true,
+		Option:  option,
+	})
+}
+
+func (mc *ModifierController) UpdateOption(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateModifierOptionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	option, ok := mc.loadOption(w, r)
+	if !ok {
+		return
+	}
+
+	diff := models.AuditDiff{}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != option.Name {
+			diff["name"] = models.AuditChange{Old: option.Name, New: name}
+		}
+		option.Name = name
+	}
+	if req.PriceDelta != nil && *req.PriceDelta != option.PriceDelta {
+		diff["price_delta"] = models.AuditChange{Old: option.PriceDelta, New: *req.PriceDelta}
+		option.PriceDelta = *req.PriceDelta
+	}
+	if req.Position != nil && *req.Position != option.Position {
+		diff["position"] = models.AuditChange{Old: option.Position, New: *req.Position}
+		option.Position = *req.Position
+	}
+	if req.IsAvailable != nil && *req.IsAvailable != option.IsAvailable {
+		diff["is_available"] = models.AuditChange{Old: option.IsAvailable, New: *req.IsAvailable}
+		option.IsAvailable = *req.IsAvailable
+	}
+
+	// Allergen changes get their own audit entry, as for menu items.
+	allergenDiff := models.AuditDiff{}
+
+	if req.Allergens != nil {
+		if err := validateTags(*req.Allergens, nil); err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		allergens := normalizeTags(*req.Allergens, models.AllAllergens)
+		if !slices.Equal(allergens, option.Allergens) {
+			allergenDiff["allergens"] = models.AuditChange{Old: option.Allergens, New: allergens}
+		}
+		if !option.AllergensDeclared {
+			allergenDiff["allergens_declared"] = models.AuditChange{Old: false, New: true}
+		}
+		option.Allergens = allergens
+		option.AllergensDeclared = true
+	}
+
+	if err := validateModifierOption(option); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(diff) > 0 || len(allergenDiff) > 0 {
+		if err := mc.modifierRepo.UpdateOption(option); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error updating option",
+			})
+			return
+		}
+	}
+
+	if len(diff) > 0 {
+		mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionModifierOptionUpdated, models.AuditTargetModifierOption, option.Id.String(), diff)
+	}
+	if len(allergenDiff) > 0 {
+		mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionModifierOptionAllergensChanged, models.AuditTargetModifierOption, option.Id.String(), allergenDiff)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.ModifierOptionResponse{
+		Success: true,
+		Option:  option,
+	})
+}
+
+func (mc *ModifierController) DeleteOption(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	option, ok := mc.loadOption(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := mc.modifierRepo.DeleteOption(option.GroupId, option.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error deleting option",
+		})
+		return
+	}
+
+	if !deleted {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Option not found",
+		})
+		return
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionModifierOptionDeleted, models.AuditTargetModifierOption, option.Id.String(), map[string]any{
+		"group_id": option.GroupId,
+		"name":     option.Name,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Option deleted",
+	})
+}
+
+// SetItemGroups replaces the modifier groups of an item. An empty list
+// removes them all.
+func (mc *ModifierController) SetItemGroups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.SetItemModifierGroupsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	item, ok := mc.loadItem(w, r, middlewares.GetCurrentRestaurant(r).Id)
+	if !ok {
+		return
+	}
+
+	set, err := mc.modifierRepo.SetItemGroups(item.RestaurantId, item.Id, req.GroupIds)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error updating item modifier groups",
+		})
+		return
+	}
+
+	if !set {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "group_ids must be distinct modifier groups of this restaurant",
+		})
+		return
+	}
+
+	oldIds := make([]uuid.UUID, len(item.ModifierGroups))
+	for i, group := range item.ModifierGroups {
+		oldIds[i] = group.Id
+	}
+
+	mc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuItemModifiersSet, models.AuditTargetMenuItem, item.Id.String(), models.AuditDiff{
+		"modifier_group_ids": models.AuditChange{Old: oldIds, New: req.GroupIds},
+	})
+
+	if item.ModifierGroups, err = mc.modifierRepo.GetItemGroups(item.Id); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MenuItemResponse{
+		Success: true,
+		Item:    item,
+	})
+}
+
+// PriceItem checks a configuration of an item of the active restaurant and
+// returns its price. Items can only be priced while a menu of their
+// category is open.
+func (mc *ModifierController) PriceItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	mc.priceItem(w, r, middlewares.GetCurrentRestaurant(r).Id)
+}
+
+// PricePublicItem is PriceItem for guests, who name the restaurant in the
+// path.
+func (mc *ModifierController) PricePublicItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	restaurantId, err := uuid.Parse(r.PathValue("restaurantId"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid restaurant id",
+		})
+		return
+	}
+
+	mc.priceItem(w, r, restaurantId)
+}
+
+func (mc *ModifierController) priceItem(w http.ResponseWriter, r *http.Request, restaurantId uuid.UUID) {
+	var req models.PriceItemRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	item, ok := mc.loadItem(w, r, restaurantId)
+	if !ok {
+		return
+	}
+
+	if err := mc.translationService.TranslateItem(restaurantId, utils.GetLocales(r), item); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	priced, violations, err := mc.menuService.PriceItem(restaurantId, item, req.OptionIds, req.Quantity, time.Now())
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if len(violations) > 0 {
+		utils.WriteJSON(w, http.StatusUnprocessableEntity, models.ConfigurationErrorResponse{
+			Success:    false,
+			Error:      violations[0].Message,
+			Code:       "invalid_configuration",
+			Violations: violations,
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.PricedItemResponse{
+		Success: true,
+		Item:    priced,
+	})
+}
+
+func (mc *ModifierController) loadGroup(w http.ResponseWriter, r *http.Request) (*models.ModifierGroup, bool) {
+	groupId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid modifier group id",
+		})
+		return nil, false
+	}
+
+	group, err := mc.modifierRepo.GetGroup(middlewares.GetCurrentRestaurant(r).Id, groupId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	if group == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Modifier group not found",
+		})
+		return nil, false
+	}
+
+	return group, true
+}
+
+// loadOption looks up the {optionId} option of the {id} group.
+func (mc *ModifierController) loadOption(w http.ResponseWriter, r *http.Request) (*models.ModifierOption, bool) {
+	group, ok := mc.loadGroup(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	optionId, err := uuid.Parse(r.PathValue("optionId"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid option id",
+		})
+		return nil, false
+	}
+
+	for _, option := range group.Options {
+		if option.Id == optionId {
+			return option, true
+		}
+	}
+
+	utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+		Success: false,
+		Error:   "Option not found",
+	})
+	return nil, false
+}
+
+func (mc *ModifierController) loadItem(w http.ResponseWriter, r *http.Request, restaurantId uuid.UUID) (*models.MenuItem, bool) {
+	itemId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid item id",
+		})
+		return nil, false
+	}
+
+	item, err := mc.menuService.GetItem(restaurantId, itemId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	if item == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Item not found",
+		})
+		return nil, false
+	}
+
+	return item, true
+}
+
+func validateModifierGroup(group *models.ModifierGroup) error {
+	if err := validateRestaurantName("name", group.Name); err != nil {
+		return err
+	}
+	if group.MinSelections < 0 {
+		return fmt.Errorf("min_selections must not be negative")
+	}
+	if group.MaxSelections != nil {
+		if *group.MaxSelections < 1 {
+			return fmt.Errorf("max_selections must be at least 1")
+		}
+		if *group.MaxSelections < group.MinimumSelections() {
+			return fmt.Errorf("max_selections must not be less than min_selections")
+		}
+	}
+
+	return nil
+}
+
+func validateModifierOption(option *models.ModifierOption) error {
+	if err := validateRestaurantName("name", option.Name); err != nil {
+		return err
+	}
+	if option.PriceDelta < -maxMenuPrice || option.PriceDelta > maxMenuPrice {
+		return fmt.Errorf("price_delta must be between -%d and %d", maxMenuPrice, maxMenuPrice)
+	}
+	if option.Position < 0 {
+		return fmt.Errorf("position must not be negative")
+	}
+
+	return nil
+}
+
+func equalIntPointers(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
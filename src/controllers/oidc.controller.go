@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const oidcStateCookieName = "oidc-state"
+
+type OIDCController struct {
+	oidcService      *services.OIDCService
+	sessionService   *services.SessionService
+	twoFactorService *services.TwoFactorService
+	auditService     *services.AuditService
+	ctx              *models.AppContext
+}
+
+func NewOIDCController(ctx *models.AppContext) *OIDCController {
+	return &OIDCController{
+		oidcService:      services.NewOIDCService(ctx),
+		sessionService:   services.NewSessionService(ctx),
+		twoFactorService: services.NewTwoFactorService(ctx),
+		auditService:     services.NewAuditService(ctx),
+		ctx:              ctx,
+	}
+}
+
+func (oc *OIDCController) ListProviders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	providers := []*models.OIDCProvider{}
+	for _, provider := range oc.oidcService.Providers() {
+		providers = append(providers, &models.OIDCProvider{
+			Name:        provider.Name,
+			DisplayName: provider.DisplayName,
+			LoginURL:    "/api/auth/oidc/" + provider.Name + "/login",
+		})
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.OIDCProvidersResponse{
+		Success:   true,
+		Providers: providers,
+	})
+}
+
+// Login redirects the browser to the provider. The state is also kept in
+// a cookie so a callback can only complete the login it was started from.
+func (oc *OIDCController) Login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	oc.redirectToProvider(w, r, nil)
+}
+
+// LinkIdentity redirects the signed in user to the provider, and the
+// callback links the provider to their account. This is the only way an
+// identity is added to an existing account.
+func (oc *OIDCController) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	oc.redirectToProvider(w, r, middlewares.GetCurrentUser(r))
+}
+
+func (oc *OIDCController) redirectToProvider(w http.ResponseWriter, r *http.Request, linkUser *models.User) {
+	returnTo := safeReturnPath(r.URL.Query().Get("return_to"))
+
+	authURL, state, err := oc.oidcService.Begin(r.Context(), r.PathValue("provider"), returnTo, linkUser)
+	if errors.Is(err, services.ErrUnknownOIDCProvider) {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Unknown sign-in provider",
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to start OIDC login: %v", err)
+		utils.WriteJSON(w, http.StatusBadGateway, models.ErrorResponse{
+			Success: false,
+			Error:   "Sign-in provider is unavailable",
+		})
+		return
+	}
+
+	// Lax, not Strict: the callback is a cross-site redirect from the
+	// provider and must still carry this cookie.
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     "/api/auth/oidc",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int((time.Duration(oc.ctx.Config.OIDC.StateTTLMinutes) * time.Minute).Seconds()),
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback completes the login and sends the browser back to the
+// frontend, with an error code in the query when it failed.
+func (oc *OIDCController) Callback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	provider := r.PathValue("provider")
+	query := r.URL.Query()
+
+	cookie, cookieErr := r.Cookie(oidcStateCookieName)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    "",
+		Path:     "/api/auth/oidc",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   -1,
+	})
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		oc.redirectToFrontend(w, r, "/login", url.Values{"error": {"provider_" + providerErr}})
+		return
+	}
+
+	state := query.Get("state")
+	if state == "" || query.Get("code") == "" || cookieErr != nil || cookie.Value != state {
+		oc.redirectToFrontend(w, r, "/login", url.Values{"error": {"invalid_state"}})
+		return
+	}
+
+	user, pending, err := oc.oidcService.Complete(r.Context(), provider, state, query.Get("code"))
+	if err != nil {
+		code := "sign_in_failed"
+
+		switch {
+		case errors.Is(err, services.ErrInvalidOIDCState), errors.Is(err, services.ErrUnknownOIDCProvider):
+			code = "invalid_state"
+		case errors.Is(err, services.ErrOIDCNoAccount):
+			code = "no_account"
+		case errors.Is(err, services.ErrOIDCLinkRequired):
+			code = "link_required"
+		case errors.Is(err, services.ErrOIDCIdentityTaken):
+			code = "identity_taken"
+		case errors.Is(err, services.ErrAccountDisabled):
+			code = "account_disabled"
+		default:
+			log.Printf("ERROR: OIDC login with %s failed: %v", provider, err)
+		}
+
+		// A failed link leaves the user signed in, they go back to where
+		// they started it.
+		if pending != nil && pending.LinkUserId != nil {
+			oc.redirectToFrontend(w, r, pending.ReturnTo, url.Values{"error": {code}})
+			return
+		}
+
+		oc.auditService.Record(r, nil, models.AuditActionLoginFailed, models.AuditTargetUser, "", map[string]any{
+			"method": "oidc:" + provider,
+			"reason": code,
+		})
+
+		oc.redirectToFrontend(w, r, "/login", url.Values{"error": {code}})
+		return
+	}
+
+	returnTo := pending.ReturnTo
+
+	if pending.LinkUserId != nil {
+		oc.auditService.Record(r, user, models.AuditActionIdentityLinked, models.AuditTargetUser, user.Id.String(), map[string]any{
+			"provider": provider,
+		})
+
+		oc.redirectToFrontend(w, r, returnTo, url.Values{"linked": {provider}})
+		return
+	}
+
+	if user.IsTwoFactorEnabled() {
+		mfaToken, err := oc.twoFactorService.CreateChallenge(user.Id)
+		if err != nil {
+			oc.redirectToFrontend(w, r, "/login", url.Values{"error": {"sign_in_failed"}})
+			return
+		}
+
+		// The challenge goes in a cookie, tokens in URLs end up in
+		// browser history and server logs.
+		oc.twoFactorService.SetChallengeCookie(w, mfaToken)
+		oc.redirectToFrontend(w, r, "/login/2fa", url.Values{"return_to": {returnTo}})
+		return
+	}
+
+	token, _, err := oc.sessionService.CreateSession(user.Id, r)
+	if err != nil {
+		oc.redirectToFrontend(w, r, "/login", url.Values{"error": {"sign_in_failed"}})
+		return
+	}
+
+	oc.sessionService.SetSessionCookie(w, token)
+
+	oc.auditService.Record(r, user, models.AuditActionLoginSucceeded, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"method": "oidc:" + provider,
+	})
+
+	oc.redirectToFrontend(w, r, returnTo, nil)
+}
+
+func (oc *OIDCController) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	identities, err := oc.oidcService.GetUserIdentities(middlewares.GetCurrentUser(r))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.UserIdentitiesResponse{
+		Success:    true,
+		Identities: identities,
+	})
+}
+
+func (oc *OIDCController) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	identityId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid identity id",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	removed, err := oc.oidcService.UnlinkIdentity(user, identityId)
+	if errors.Is(err, services.ErrLastSignInMethod) {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Set a password before removing your only sign-in provider",
+		})
+		return
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if !removed {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Identity not found",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Identity unlinked",
+	})
+}
+
+func (oc *OIDCController) redirectToFrontend(w http.ResponseWriter, r *http.Request, path string, params url.Values) {
+	target := strings.TrimRight(oc.ctx.Config.App.FrontendURL, "/") + path
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// safeReturnPath only allows paths on the frontend itself, so the login
+// flow cannot be used as an open redirect.
+func safeReturnPath(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.ContainsAny(path, "\\\r\n") {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"restaurant-backend/src/mailer"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordController struct {
+	userRepo       *repositories.UserRepository
+	resetRepo      *repositories.PasswordResetRepository
+	sessionService *services.SessionService
+	passwordPolicy *services.PasswordPolicyService
+	auditService   *services.AuditService
+	mailer         mailer.Mailer
+	ctx            *models.AppContext
+}
+
+func NewPasswordController(ctx *models.AppContext) *PasswordController {
+	return &PasswordController{
+		userRepo:       repositories.NewUserRepository(ctx.DB),
+		resetRepo:      repositories.NewPasswordResetRepository(ctx.DB),
+		sessionService: services.NewSessionService(ctx),
+		passwordPolicy: services.NewPasswordPolicyService(ctx),
+		auditService:   services.NewAuditService(ctx),
+		mailer:         mailer.NewMailer(ctx.Config.Mail),
+		ctx:            ctx,
+	}
+}
+
+// ForgotPassword always answers with the same message so that it cannot
+// be used to find out which emails are registered.
+func (pc *PasswordController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ForgotPasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "email is required",
+		})
+		return
+	}
+
+	user, err := pc.userRepo.GetUserByEmail(email)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if user != nil {
+		if err := pc.sendResetToken(user); err != nil {
+			log.Printf("ERROR: Failed to send password reset email: %v", err)
+		}
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "If an account with this email exists, a reset link has been sent",
+	})
+}
+
+func (pc *PasswordController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ResetPasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if strings.TrimSpace(req.Token) == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "token is required",
+		})
+		return
+	}
+	tokenHash := utils.HashString(strings.TrimSpace(req.Token))
+
+	// Look the token up first so a rejected password does not burn it.
+	userId, err := pc.resetRepo.GetTokenUserId(tokenHash)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	var user *models.User
+	if userId != uuid.Nil {
+		if user, err = pc.userRepo.GetUserById(userId.String()); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+	}
+
+	if user == nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Reset token is invalid or expired",
+		})
+		return
+	}
+
+	if violations := pc.passwordPolicy.Validate(req.Password, user.Name, user.Email); len(violations) > 0 {
+		writePasswordViolations(w, violations)
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error processing password",
+		})
+		return
+	}
+
+	// The token is used up together with the password change, a failed
+	// update leaves it valid for another try.
+	if userId, err = pc.resetRepo.ResetPassword(tokenHash, hashedPassword); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error updating password",
+		})
+		return
+	}
+
+	if userId != user.Id {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Reset token is invalid or expired",
+		})
+		return
+	}
+
+	pc.auditService.Record(r, user, models.AuditActionUserPasswordReset, models.AuditTargetUser, user.Id.String(), nil)
+
+	if _, err := pc.sessionService.RevokeAllUserSessions(userId); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error ending existing sessions",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Password has been reset, please log in again",
+	})
+}
+
+func (pc *PasswordController) sendResetToken(user *models.User) error {
+	token := utils.GenerateRandomToken()
+	ttl := time.Duration(pc.ctx.Config.App.PasswordResetTTLMinutes) * time.Minute
+
+	if err := pc.resetRepo.CreateToken(user.Id, utils.HashString(token), time.Now().Add(ttl)); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", strings.TrimRight(pc.ctx.Config.App.FrontendURL, "/"), token)
+
+	return pc.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nUse the link below to choose a new password. It expires in %d minutes.\n\n%s\n\nIf you did not request a reset, you can ignore this email.",
+			user.Name, pc.ctx.Config.App.PasswordResetTTLMinutes, link),
+	})
+}
+
+// writePasswordViolations reports every broken password rule so clients
+// can show them next to the field.
+func writePasswordViolations(w http.ResponseWriter, violations []models.PasswordViolation) {
+	utils.WriteJSON(w, http.StatusBadRequest, models.PasswordPolicyErrorResponse{
+		Success:    false,
+		Error:      violations[0].Message,
+		Code:       "weak_password",
+		Violations: violations,
+	})
+}
@@ -0,0 +1,308 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const maxDataRequestTextLength = 1000
+
+type PrivacyController struct {
+	privacyService *services.PrivacyService
+	auditService   *services.AuditService
+	ctx            *models.AppContext
+}
+
+func NewPrivacyController(ctx *models.AppContext) *PrivacyController {
+	return &PrivacyController{
+		privacyService: services.NewPrivacyService(ctx),
+		auditService:   services.NewAuditService(ctx),
+		ctx:            ctx,
+	}
+}
+
+// Export hands the current user everything stored about them, as a ZIP
+// bundle or, with ?format=json, as a single JSON document.
+func (pc *PrivacyController) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "zip"
+	}
+	if format != "zip" && format != "json" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid format, use zip or json",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	export, err := pc.privacyService.Export(user)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error exporting data",
+		})
+		return
+	}
+
+	pc.auditService.Record(r, user, models.AuditActionDataExported, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"format": format,
+	})
+
+	filename := "personal-data-" + export.GeneratedAt.Format("20060102-150405")
+
+	if format == "json" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		utils.WriteJSON(w, http.StatusOK, export)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	if err := pc.privacyService.WriteArchive(w, export); err != nil {
+		// The status line is already out, all that is left is to cut
+		// the download short.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// RequestErasure queues the current user's account for erasure. Nothing is
+// removed until an admin completes the request.
+func (pc *PrivacyController) RequestErasure(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ErasureRequest
+
+	// The body is optional, a reason is not required.
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > maxDataRequestTextLength {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   fmt.Sprintf("reason must be no more than %d characters long", maxDataRequestTextLength),
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	request, created, err := pc.privacyService.RequestErasure(user, reason)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating erasure request",
+		})
+		return
+	}
+
+	if !created {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "An erasure request is already pending",
+		})
+		return
+	}
+
+	pc.auditService.Record(r, user, models.AuditActionErasureRequested, models.AuditTargetDataRequest, request.Id.String(), nil)
+
+	utils.WriteJSON(w, http.StatusAccepted, models.DataRequestResponse{
+		Success:     true,
+		Message:     "Erasure request received",
+		DataRequest: request,
+	})
+}
+
+// ListRequests is the admin queue, filtered by ?status and pending by
+// default. Use status=all to see processed requests too.
+func (pc *PrivacyController) ListRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = models.DataRequestStatusPending
+	case "all":
+		status = ""
+	case models.DataRequestStatusPending, models.DataRequestStatusCompleted, models.DataRequestStatusRejected:
+	default:
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid status",
+		})
+		return
+	}
+
+	page, pageSize := utils.GetPagination(r)
+
+	requests, total, err := pc.privacyService.ListRequests(middlewares.GetCurrentRestaurant(r).Id, status, pageSize, (page-1)*pageSize)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.DataRequestsPageResponse{
+		Success:      true,
+		DataRequests: requests,
+		Page:         page,
+		PageSize:     pageSize,
+		Total:        total,
+	})
+}
+
+// CompleteRequest anonymizes the requester. Orders and other financial
+// records keep pointing at the anonymized account.
+func (pc *PrivacyController) CompleteRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	restaurant := middlewares.GetCurrentRestaurant(r)
+	complete := func(request *models.DataRequest, processor *models.User, note string) (bool, error) {
+		return pc.privacyService.Complete(restaurant, request, processor, note)
+	}
+
+	pc.processRequest(w, r, complete, models.AuditActionErasureCompleted, "Personal data erased")
+}
+
+func (pc *PrivacyController) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pc.processRequest(w, r, pc.privacyService.Reject, models.AuditActionErasureRejected, "Erasure request rejected")
+}
+
+func (pc *PrivacyController) processRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	process func(*models.DataRequest, *models.User, string) (bool, error),
+	action, message string,
+) {
+	requestId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request id",
+		})
+		return
+	}
+
+	var req models.ProcessDataRequestRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if len(note) > maxDataRequestTextLength {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   fmt.Sprintf("note must be no more than %d characters long", maxDataRequestTextLength),
+		})
+		return
+	}
+
+	restaurant := middlewares.GetCurrentRestaurant(r)
+
+	request, err := pc.privacyService.GetRequest(restaurant.Id, requestId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if request == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Request not found",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if request.UserId == user.Id {
+		utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   "You cannot process your own request",
+		})
+		return
+	}
+
+	processed, err := process(request, user, note)
+	if errors.Is(err, services.ErrErasureOutsideOrganization) {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "User also works for another organization, remove them from this restaurant instead",
+		})
+		return
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error processing request",
+		})
+		return
+	}
+
+	if !processed {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Request has already been processed",
+		})
+		return
+	}
+
+	pc.auditService.Record(r, user, action, models.AuditTargetDataRequest, request.Id.String(), map[string]any{
+		"user_id": request.UserId,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.DataRequestResponse{
+		Success:     true,
+		Message:     message,
+		DataRequest: request,
+	})
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+)
+
+type RestaurantController struct {
+	restaurantService *services.RestaurantService
+	auditService      *services.AuditService
+	ctx               *models.AppContext
+}
+
+func NewRestaurantController(ctx *models.AppContext) *RestaurantController {
+	return &RestaurantController{
+		restaurantService: services.NewRestaurantService(ctx),
+		auditService:      services.NewAuditService(ctx),
+		ctx:               ctx,
+	}
+}
+
+// ListRestaurants returns the restaurants the caller can select with the
+// X-Restaurant-Id header, with their role in each.
+func (rc *RestaurantController) ListRestaurants(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	restaurants, err := rc.restaurantService.GetUserRestaurants(middlewares.GetCurrentUser(r))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.RestaurantsResponse{
+		Success:     true,
+		Restaurants: restaurants,
+	})
+}
+
+// CreateRestaurant adds a restaurant to the organization of the active
+// one. The caller becomes its owner.
+func (rc *RestaurantController) CreateRestaurant(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateRestaurantRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := validateRestaurantName("name", name); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+	current := middlewares.GetCurrentRestaurant(r)
+
+	restaurant, err := rc.restaurantService.CreateRestaurant(user, current.OrganizationId, name, strings.TrimSpace(req.Timezone))
+	if !rc.handleCreateError(w, err) {
+		return
+	}
+
+	rc.auditService.Record(r, user, models.AuditActionRestaurantCreated, models.AuditTargetRestaurant, restaurant.Id.String(), map[string]any{
+		"name":     restaurant.Name,
+		"timezone": restaurant.Timezone,
+	})
+
+	utils.WriteJSON(w, http.StatusCreated, models.RestaurantResponse{
+		Success:    true,
+		Restaurant: restaurant,
+	})
+}
+
+// CreateOrganization starts a separate organization with its first
+// restaurant, owned by the caller.
+func (rc *RestaurantController) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateOrganizationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	restaurantName := strings.TrimSpace(req.RestaurantName)
+
+	err := validateRestaurantName("name", name)
+	if err == nil {
+		err = validateRestaurantName("restaurant_name", restaurantName)
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	organization, restaurant, err := rc.restaurantService.CreateOrganization(user, name, restaurantName, strings.TrimSpace(req.Timezone))
+	if !rc.handleCreateError(w, err) {
+		return
+	}
+
+	rc.auditService.Record(r, user, models.AuditActionOrganizationCreated, models.AuditTargetRestaurant, restaurant.Id.String(), map[string]any{
+		"organization_id": organization.Id,
+		"name":            organization.Name,
+		"restaurant_name": restaurant.Name,
+	})
+
+	utils.WriteJSON(w, http.StatusCreated, models.RestaurantResponse{
+		Success:      true,
+		Organization: organization,
+		Restaurant:   restaurant,
+	})
+}
+
+func (rc *RestaurantController) handleCreateError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, services.ErrInvalidTimezone) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "timezone must be an IANA name such as Europe/Berlin",
+		})
+		return false
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating restaurant",
+		})
+		return false
+	}
+
+	return true
+}
+
+func validateRestaurantName(field, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("%s must be no more than 100 characters long", field)
+	}
+
+	return nil
+}
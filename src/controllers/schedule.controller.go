@@ -0,0 +1,551 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxMenuWindows   = 100
+	maxMenuOverrides = 100
+)
+
+type ScheduleController struct {
+	scheduleRepo       *repositories.ScheduleRepository
+	menuService        *services.MenuService
+	translationService *services.TranslationService
+	auditService       *services.AuditService
+	ctx                *models.AppContext
+}
+
+func NewScheduleController(ctx *models.AppContext) *ScheduleController {
+	return &ScheduleController{
+		scheduleRepo:       repositories.NewScheduleRepository(ctx.DB),
+		menuService:        services.NewMenuService(ctx),
+		translationService: services.NewTranslationService(ctx),
+		auditService:       services.NewAuditService(ctx),
+		ctx:                ctx,
+	}
+}
+
+// GetOrderable answers what can be ordered now, or at ?at=. It takes the
+// filters of ListItems and translates texts for Accept-Language.
+func (sc *ScheduleController) GetOrderable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	restaurant := middlewares.GetCurrentRestaurant(r)
+
+	filter, err := parseMenuItemFilter(r, restaurant.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	at, err := parseAtParam(r.URL.Query().Get("at"), restaurant)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid at, use RFC 3339 or 2006-01-02T15:04",
+		})
+		return
+	}
+
+	openMenus, categories, err := sc.menuService.GetOrderableMenu(restaurant, filter, at)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if err := sc.translationService.TranslateMenu(restaurant.Id, utils.GetLocales(r), categories, openMenus); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+	w.Header().Set("Vary", "Accept-Language")
+
+	location := services.RestaurantLocation(restaurant)
+
+	utils.WriteJSON(w, http.StatusOK, models.OrderableResponse{
+		Success:    true,
+		At:         at.In(location),
+		Timezone:   location.String(),
+		OpenMenus:  openMenus,
+		Categories: categories,
+	})
+}
+
+func (sc *ScheduleController) ListMenus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	menus, err := sc.scheduleRepo.GetMenus(middlewares.GetCurrentRestaurant(r).Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.ScheduledMenusResponse{
+		Success: true,
+		Menus:   menus,
+	})
+}
+
+// CreateMenu creates an empty menu. Until categories and a schedule are
+// set it changes nothing.
+func (sc *ScheduleController) CreateMenu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CreateScheduledMenuRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	menu := &models.Menu{
+		RestaurantId: middlewares.GetCurrentRestaurant(r).Id,
+		Name:         strings.TrimSpace(req.Name),
+		Description:  strings.TrimSpace(req.Description),
+		IsAvailable:  req.IsAvailable == nil || *req.IsAvailable,
+		CategoryIds:  []uuid.UUID{},
+		Windows:      []*models.MenuWindow{},
+		Overrides:    []*models.MenuOverride{},
+	}
+
+	if err := validateMenuEntry(menu.Name, menu.Description, req.Position); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := sc.scheduleRepo.CreateMenu(menu, req.Position); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error creating menu",
+		})
+		return
+	}
+
+	sc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionScheduledMenuCreated, models.AuditTargetMenu, menu.Id.String(), map[string]any{
+		"name": menu.Name,
+	})
+
+	utils.WriteJSON(w, http.StatusCreated, models.ScheduledMenuResponse{
+		Success: true,
+		Menu:    menu,
+	})
+}
+
+func (sc *ScheduleController) GetMenu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	menu, ok := sc.loadMenu(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.ScheduledMenuResponse{
+		Success: true,
+		Menu:    menu,
+	})
+}
+
+func (sc *ScheduleController) UpdateMenu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateScheduledMenuRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	menu, ok := sc.loadMenu(w, r)
+	if !ok {
+		return
+	}
+
+	diff := models.AuditDiff{}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != menu.Name {
+			diff["name"] = models.AuditChange{Old: menu.Name, New: name}
+		}
+		menu.Name = name
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if description != menu.Description {
+			diff["description"] = models.AuditChange{Old: menu.Description, New: description}
+		}
+		menu.Description = description
+	}
+	if req.Position != nil && *req.Position != menu.Position {
+		diff["position"] = models.AuditChange{Old: menu.Position, New: *req.Position}
+		menu.Position = *req.Position
+	}
+	if req.IsAvailable != nil && *req.IsAvailable != menu.IsAvailable {
+		diff["is_available"] = models.AuditChange{Old: menu.IsAvailable, New: *req.IsAvailable}
+		menu.IsAvailable = *req.IsAvailable
+	}
+
+	if err := validateMenuEntry(menu.Name, menu.Description, &menu.Position); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(diff) > 0 {
+		if err := sc.scheduleRepo.UpdateMenu(menu); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error updating menu",
+			})
+			return
+		}
+
+		sc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionScheduledMenuUpdated, models.AuditTargetMenu, menu.Id.String(), diff)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.ScheduledMenuResponse{
+		Success: true,
+		Menu:    menu,
+	})
+}
+
+// DeleteMenu keeps the categories. Those in no other menu become orderable
+// at any time.
+func (sc *ScheduleController) DeleteMenu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	menu, ok := sc.loadMenu(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := sc.scheduleRepo.DeleteMenu(menu.RestaurantId, menu.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error deleting menu",
+		})
+		return
+	}
+
+	if !deleted {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Menu not found",
+		})
+		return
+	}
+
+	sc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionScheduledMenuDeleted, models.AuditTargetMenu, menu.Id.String(), map[string]any{
+		"name":         menu.Name,
+		"category_ids": menu.CategoryIds,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Menu deleted",
+	})
+}
+
+// SetCategories replaces the categories of the menu, shown in the order
+// given.
+func (sc *ScheduleController) SetCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.SetMenuCategoriesRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	menu, ok := sc.loadMenu(w, r)
+	if !ok {
+		return
+	}
+
+	set, err := sc.scheduleRepo.SetMenuCategories(menu.RestaurantId, menu.Id, req.CategoryIds)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error updating menu categories",
+		})
+		return
+	}
+
+	if !set {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "category_ids must be distinct categories of this restaurant",
+		})
+		return
+	}
+
+	categoryIds := req.CategoryIds
+	if categoryIds == nil {
+		categoryIds = []uuid.UUID{}
+	}
+
+	sc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuCategoriesSet, models.AuditTargetMenu, menu.Id.String(), models.AuditDiff{
+		"category_ids": models.AuditChange{Old: menu.CategoryIds, New: categoryIds},
+	})
+
+	menu.CategoryIds = categoryIds
+
+	utils.WriteJSON(w, http.StatusOK, models.ScheduledMenuResponse{
+		Success: true,
+		Menu:    menu,
+	})
+}
+
+// SetSchedule replaces the weekly windows and date overrides of the menu.
+func (sc *ScheduleController) SetSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.SetMenuScheduleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if req.Windows == nil {
+		req.Windows = []*models.MenuWindow{}
+	}
+	if req.Overrides == nil {
+		req.Overrides = []*models.MenuOverride{}
+	}
+
+	if err := validateMenuSchedule(req.Windows, req.Overrides); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	menu, ok := sc.loadMenu(w, r)
+	if !ok {
+		return
+	}
+
+	if err := sc.scheduleRepo.SetSchedule(menu.Id, req.Windows, req.Overrides); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error updating menu schedule",
+		})
+		return
+	}
+
+	sc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionMenuScheduleSet, models.AuditTargetMenu, menu.Id.String(), models.AuditDiff{
+		"windows":   models.AuditChange{Old: menu.Windows, New: req.Windows},
+		"overrides": models.AuditChange{Old: menu.Overrides, New: req.Overrides},
+	})
+
+	menu, ok = sc.loadMenu(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.ScheduledMenuResponse{
+		Success: true,
+		Menu:    menu,
+	})
+}
+
+func (sc *ScheduleController) loadMenu(w http.ResponseWriter, r *http.Request) (*models.Menu, bool) {
+	menuId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid menu id",
+		})
+		return nil, false
+	}
+
+	menu, err := sc.scheduleRepo.GetMenu(middlewares.GetCurrentRestaurant(r).Id, menuId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	if menu == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Menu not found",
+		})
+		return nil, false
+	}
+
+	return menu, true
+}
+
+// validateMenuSchedule also writes override dates in their canonical form
+// and rejects overrides that share a day, since they would contradict
+// each other.
+func validateMenuSchedule(windows []*models.MenuWindow, overrides []*models.MenuOverride) error {
+	if len(windows) > maxMenuWindows {
+		return fmt.Errorf("a menu can have no more than %d windows", maxMenuWindows)
+	}
+	if len(overrides) > maxMenuOverrides {
+		return fmt.Errorf("a menu can have no more than %d overrides", maxMenuOverrides)
+	}
+
+	for i, window := range windows {
+		if window == nil {
+			return fmt.Errorf("windows[%d] is required", i)
+		}
+		if window.Weekday < 0 || window.Weekday > 6 {
+			return fmt.Errorf("windows[%d]: weekday must be between 0 (Sunday) and 6 (Saturday)", i)
+		}
+		if err := validateMenuHours(window.Start, window.End); err != nil {
+			return fmt.Errorf("windows[%d]: %v", i, err)
+		}
+	}
+
+	for i, override := range overrides {
+		if override == nil {
+			return fmt.Errorf("overrides[%d] is required", i)
+		}
+
+		startDate, err := time.Parse(time.DateOnly, override.StartDate)
+		if err != nil {
+			return fmt.Errorf("overrides[%d]: start_date must look like 2006-01-02", i)
+		}
+		endDate, err := time.Parse(time.DateOnly, override.EndDate)
+		if err != nil {
+			return fmt.Errorf("overrides[%d]: end_date must look like 2006-01-02", i)
+		}
+		if endDate.Before(startDate) {
+			return fmt.Errorf("overrides[%d]: end_date must not be before start_date", i)
+		}
+		override.StartDate = startDate.Format(time.DateOnly)
+		override.EndDate = endDate.Format(time.DateOnly)
+
+		if (override.Start == nil) != (override.End == nil) {
+			return fmt.Errorf("overrides[%d]: give both start and end, or neither for the whole day", i)
+		}
+		if override.Start != nil {
+			if !override.IsAvailable {
+				return fmt.Errorf("overrides[%d]: start and end only apply when is_available is true", i)
+			}
+			if err := validateMenuHours(*override.Start, *override.End); err != nil {
+				return fmt.Errorf("overrides[%d]: %v", i, err)
+			}
+		}
+
+		override.Note = strings.TrimSpace(override.Note)
+		if len(override.Note) > 200 {
+			return fmt.Errorf("overrides[%d]: note must be no more than 200 characters long", i)
+		}
+	}
+
+	sorted := slices.Clone(overrides)
+	slices.SortFunc(sorted, func(a, b *models.MenuOverride) int {
+		return strings.Compare(a.StartDate, b.StartDate)
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].StartDate <= sorted[i-1].EndDate {
+			return fmt.Errorf("overrides from %s and %s overlap", sorted[i-1].StartDate, sorted[i].StartDate)
+		}
+	}
+
+	return nil
+}
+
+func validateMenuHours(start, end models.ClockTime) error {
+	if start >= models.MinutesPerDay {
+		return fmt.Errorf("start must be before 24:00")
+	}
+	if end == 0 {
+		return fmt.Errorf("end must be after 00:00, use 24:00 for midnight")
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+
+	return nil
+}
+
+// parseAtParam reads ?at= as RFC 3339, or as 2006-01-02T15:04 in the
+// restaurant's time zone. Without it the time is now.
+func parseAtParam(value string, restaurant *models.Restaurant) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	return time.ParseInLocation("2006-01-02T15:04", value, services.RestaurantLocation(restaurant))
+}
@@ -0,0 +1,370 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxTranslationLocales caps how many locales one missing list covers.
+const maxTranslationLocales = 20
+
+type TranslationController struct {
+	translationRepo *repositories.TranslationRepository
+	auditService    *services.AuditService
+	ctx             *models.AppContext
+}
+
+func NewTranslationController(ctx *models.AppContext) *TranslationController {
+	return &TranslationController{
+		translationRepo: repositories.NewTranslationRepository(ctx.DB),
+		auditService:    services.NewAuditService(ctx),
+		ctx:             ctx,
+	}
+}
+
+// ListTranslations filters by entity_type, entity_id and locale.
+func (tc *TranslationController) ListTranslations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := models.TranslationFilter{
+		RestaurantId: middlewares.GetCurrentRestaurant(r).Id,
+		EntityType:   query.Get("entity_type"),
+	}
+
+	if filter.EntityType != "" {
+		if _, ok := models.TranslatableFields[filter.EntityType]; !ok {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid entity_type",
+			})
+			return
+		}
+	}
+
+	if value := query.Get("entity_id"); value != "" {
+		entityId, err := uuid.Parse(value)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid entity_id",
+			})
+			return
+		}
+		filter.EntityId = &entityId
+	}
+
+	if value := query.Get("locale"); value != "" {
+		locale, ok := utils.NormalizeLocale(value)
+		if !ok {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   "Invalid locale",
+			})
+			return
+		}
+		filter.Locale = locale
+	}
+
+	translations, err := tc.translationRepo.GetTranslations(filter)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.TranslationsResponse{
+		Success:      true,
+		Translations: translations,
+	})
+}
+
+// ListMissing lists the texts still to be translated into ?locale=, which
+// takes a comma separated list. Without it every locale the restaurant
+// already has translations in is checked. Translations made before their
+// text changed are listed as outdated.
+func (tc *TranslationController) ListMissing(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	restaurantId := middlewares.GetCurrentRestaurant(r).Id
+
+	locales := []string{}
+	for _, value := range splitListParam(r.URL.Query()["locale"]) {
+		locale, ok := utils.NormalizeLocale(value)
+		if !ok {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Invalid locale %q", value),
+			})
+			return
+		}
+		if !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+
+	if len(locales) == 0 {
+		var err error
+		if locales, err = tc.translationRepo.GetLocales(restaurantId); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+	}
+
+	if len(locales) > maxTranslationLocales {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   fmt.Sprintf("No more than %d locales at once", maxTranslationLocales),
+		})
+		return
+	}
+
+	missing, err := tc.translationRepo.GetMissing(restaurantId, locales)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	counts := make(map[string]int, len(locales))
+	for _, locale := range locales {
+		counts[locale] = 0
+	}
+	for _, entry := range missing {
+		counts[entry.Locale]++
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MissingTranslationsResponse{
+		Success: true,
+		Counts:  counts,
+		Missing: missing,
+	})
+}
+
+// SetTranslation adds or replaces the translation of one field of one
+// entity, as in PUT /api/menu/translations/menu_item/{id}/name/de.
+func (tc *TranslationController) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.SetTranslationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	translation, ok := parseTranslationPath(w, r)
+	if !ok {
+		return
+	}
+
+	translation.Value = strings.TrimSpace(req.Value)
+	if translation.Value == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "value is required, delete the translation to fall back to the original text",
+		})
+		return
+	}
+	if len(translation.Value) > 1000 {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "value must be no more than 1000 characters long",
+		})
+		return
+	}
+
+	restaurantId := middlewares.GetCurrentRestaurant(r).Id
+
+	source, found, err := tc.translationRepo.GetSource(restaurantId, translation.EntityType, translation.EntityId, translation.Field)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if !found {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "No text to translate, the entity does not exist or the field is empty",
+		})
+		return
+	}
+	translation.Source = source
+
+	existing, err := tc.translationRepo.GetTranslation(restaurantId, translation.EntityType, translation.EntityId, translation.Field, translation.Locale)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if err := tc.translationRepo.SetTranslation(restaurantId, translation); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error saving translation",
+		})
+		return
+	}
+
+	var oldValue any
+	if existing != nil {
+		oldValue = existing.Value
+	}
+
+	if existing == nil || existing.Value != translation.Value {
+		tc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionTranslationSet, translation.EntityType, translation.EntityId.String(), map[string]any{
+			"field":  translation.Field,
+			"locale": translation.Locale,
+			"value":  models.AuditChange{Old: oldValue, New: translation.Value},
+		})
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.TranslationResponse{
+		Success:     true,
+		Translation: translation,
+	})
+}
+
+// DeleteTranslation makes the field fall back along the locale chain, and
+// in the end to the original text.
+func (tc *TranslationController) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	translation, ok := parseTranslationPath(w, r)
+	if !ok {
+		return
+	}
+
+	restaurantId := middlewares.GetCurrentRestaurant(r).Id
+
+	existing, err := tc.translationRepo.GetTranslation(restaurantId, translation.EntityType, translation.EntityId, translation.Field, translation.Locale)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if existing == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Translation not found",
+		})
+		return
+	}
+
+	deleted, err := tc.translationRepo.DeleteTranslation(restaurantId, existing.EntityType, existing.EntityId, existing.Field, existing.Locale)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error deleting translation",
+		})
+		return
+	}
+
+	if !deleted {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "Translation not found",
+		})
+		return
+	}
+
+	tc.auditService.Record(r, middlewares.GetCurrentUser(r), models.AuditActionTranslationDeleted, existing.EntityType, existing.EntityId.String(), map[string]any{
+		"field":  existing.Field,
+		"locale": existing.Locale,
+		"value":  existing.Value,
+	})
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Translation deleted",
+	})
+}
+
+// parseTranslationPath checks that the path names a translatable field.
+func parseTranslationPath(w http.ResponseWriter, r *http.Request) (*models.Translation, bool) {
+	entityType := r.PathValue("entityType")
+
+	fields, ok := models.TranslatableFields[entityType]
+	if !ok {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid entity type",
+		})
+		return nil, false
+	}
+
+	entityId, err := uuid.Parse(r.PathValue("entityId"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid entity id",
+		})
+		return nil, false
+	}
+
+	field := r.PathValue("field")
+	if !slices.Contains(fields, field) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   fmt.Sprintf("%s can be translated in %s", entityType, strings.Join(fields, " and ")),
+		})
+		return nil, false
+	}
+
+	locale, ok := utils.NormalizeLocale(r.PathValue("locale"))
+	if !ok {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid locale, use a language tag such as de or pt-BR",
+		})
+		return nil, false
+	}
+
+	return &models.Translation{
+		EntityType: entityType,
+		EntityId:   entityId,
+		Field:      field,
+		Locale:     locale,
+	}, true
+}
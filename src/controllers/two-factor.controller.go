@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+)
+
+type TwoFactorController struct {
+	twoFactorService *services.TwoFactorService
+	auditService     *services.AuditService
+	ctx              *models.AppContext
+}
+
+func NewTwoFactorController(ctx *models.AppContext) *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorService: services.NewTwoFactorService(ctx),
+		auditService:     services.NewAuditService(ctx),
+		ctx:              ctx,
+	}
+}
+
+func (tc *TwoFactorController) Setup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if user.IsTwoFactorEnabled() {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Two-factor authentication is already enabled",
+		})
+		return
+	}
+
+	secret, uri, err := tc.twoFactorService.Setup(user)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error setting up two-factor authentication",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.TwoFactorSetupResponse{
+		Success:         true,
+		Secret:          secret,
+		ProvisioningURI: uri,
+	})
+}
+
+func (tc *TwoFactorController) Enable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if user.IsTwoFactorEnabled() {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Two-factor authentication is already enabled",
+		})
+		return
+	}
+
+	codes, err := tc.twoFactorService.Enable(user, req.Code)
+	if errors.Is(err, services.ErrInvalidMFACode) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid two-factor code",
+		})
+		return
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error enabling two-factor authentication",
+		})
+		return
+	}
+
+	tc.auditService.Record(r, user, models.AuditActionTwoFactorEnabled, models.AuditTargetUser, user.Id.String(), nil)
+
+	utils.WriteJSON(w, http.StatusOK, models.RecoveryCodesResponse{
+		Success:       true,
+		Message:       "Two-factor authentication enabled, store these recovery codes in a safe place",
+		RecoveryCodes: codes,
+	})
+}
+
+func (tc *TwoFactorController) Disable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TwoFactorDisableRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if !user.IsTwoFactorEnabled() {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Two-factor authentication is not enabled",
+		})
+		return
+	}
+
+	if tc.twoFactorService.IsRequired(user) {
+		utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   "Two-factor authentication is mandatory for your role",
+		})
+		return
+	}
+
+	if !utils.IsPasswordEqualHash(user.Password, req.Password) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Error invalid password",
+		})
+		return
+	}
+
+	if !tc.verifyCode(w, user, req.Code) {
+		return
+	}
+
+	if err := tc.twoFactorService.Disable(user); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error disabling two-factor authentication",
+		})
+		return
+	}
+
+	tc.auditService.Record(r, user, models.AuditActionTwoFactorDisabled, models.AuditTargetUser, user.Id.String(), nil)
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Two-factor authentication disabled",
+	})
+}
+
+func (tc *TwoFactorController) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if !user.IsTwoFactorEnabled() {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "Two-factor authentication is not enabled",
+		})
+		return
+	}
+
+	if !tc.verifyCode(w, user, req.Code) {
+		return
+	}
+
+	codes, err := tc.twoFactorService.RegenerateRecoveryCodes(user)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error generating recovery codes",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.RecoveryCodesResponse{
+		Success:       true,
+		Message:       "Previous recovery codes are no longer valid",
+		RecoveryCodes: codes,
+	})
+}
+
+func (tc *TwoFactorController) verifyCode(w http.ResponseWriter, user *models.User, code string) bool {
+	ok, err := tc.twoFactorService.VerifyCode(user, code)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return false
+	}
+
+	if !ok {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid two-factor code",
+		})
+		return false
+	}
+
+	return true
+}
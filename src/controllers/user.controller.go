@@ -0,0 +1,718 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type UserController struct {
+	userRepo            *repositories.UserRepository
+	restaurantRepo      *repositories.RestaurantRepository
+	roleRepo            *repositories.RoleRepository
+	sessionService      *services.SessionService
+	verificationService *services.EmailVerificationService
+	passwordPolicy      *services.PasswordPolicyService
+	auditService        *services.AuditService
+	ctx                 *models.AppContext
+}
+
+func NewUserController(ctx *models.AppContext) *UserController {
+	return &UserController{
+		userRepo:            repositories.NewUserRepository(ctx.DB),
+		restaurantRepo:      repositories.NewRestaurantRepository(ctx.DB),
+		roleRepo:            repositories.NewRoleRepository(ctx.DB),
+		sessionService:      services.NewSessionService(ctx),
+		verificationService: services.NewEmailVerificationService(ctx),
+		passwordPolicy:      services.NewPasswordPolicyService(ctx),
+		auditService:        services.NewAuditService(ctx),
+		ctx:                 ctx,
+	}
+}
+
+func (uc *UserController) GetProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.UserResponse{
+		Success: true,
+		User:    middlewares.GetCurrentUser(r),
+	})
+}
+
+func (uc *UserController) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateProfileRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+	diff := models.AuditDiff{}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := validateName(name); err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		if name != user.Name {
+			diff["name"] = models.AuditChange{Old: user.Name, New: name}
+		}
+		user.Name = name
+	}
+
+	if err := uc.userRepo.UpdateProfile(user); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error updating profile",
+		})
+		return
+	}
+
+	if len(diff) > 0 {
+		uc.auditService.Record(r, user, models.AuditActionUserProfileUpdated, models.AuditTargetUser, user.Id.String(), diff)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.UserResponse{
+		Success: true,
+		User:    user,
+	})
+}
+
+// ChangePassword requires the current password and signs the user out
+// everywhere else by rotating every session.
+func (uc *UserController) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ChangePasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if !utils.IsPasswordEqualHash(user.Password, req.CurrentPassword) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Current password is incorrect",
+		})
+		return
+	}
+
+	if violations := uc.passwordPolicy.Validate(req.NewPassword, user.Name, user.Email); len(violations) > 0 {
+		writePasswordViolations(w, violations)
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error processing password",
+		})
+		return
+	}
+
+	if err := uc.userRepo.UpdatePassword(user.Id, hashedPassword); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error updating password",
+		})
+		return
+	}
+
+	uc.auditService.Record(r, user, models.AuditActionUserPasswordChanged, models.AuditTargetUser, user.Id.String(), nil)
+
+	if _, err := uc.sessionService.RevokeAllUserSessions(user.Id); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error ending existing sessions",
+		})
+		return
+	}
+
+	// Cookie clients keep working on this device with a fresh session,
+	// bearer clients sign in again.
+	if middlewares.GetCurrentSession(r) != nil {
+		token, _, err := uc.sessionService.CreateSession(user.Id, r)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error creating session",
+			})
+			return
+		}
+
+		uc.sessionService.SetSessionCookie(w, token)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "Password changed, other sessions have been signed out",
+	})
+}
+
+func (uc *UserController) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.ChangeEmailRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	user := middlewares.GetCurrentUser(r)
+
+	if !utils.IsPasswordEqualHash(user.Password, req.Password) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Current password is incorrect",
+		})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !strings.Contains(email, "@") || !strings.Contains(email, ".") {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "invalid email format",
+		})
+		return
+	}
+
+	if email == user.Email {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "New email must differ from the current one",
+		})
+		return
+	}
+
+	exists, err := uc.userRepo.UserExists(email)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if exists {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "User with this email already exists",
+		})
+		return
+	}
+
+	if err := uc.verificationService.RequestEmailChange(user, email); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error sending verification email",
+		})
+		return
+	}
+
+	uc.auditService.Record(r, user, models.AuditActionUserEmailChanged, models.AuditTargetUser, user.Id.String(), models.AuditDiff{
+		"email": {Old: user.Email, New: email},
+	})
+
+	utils.WriteJSON(w, http.StatusAccepted, models.MessageResponse{
+		Success: true,
+		Message: "Check the new address for a verification link, the change applies once it is confirmed",
+	})
+}
+
+func (uc *UserController) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	page, pageSize := utils.GetPagination(r)
+	search := strings.TrimSpace(r.URL.Query().Get("search"))
+
+	users, total, err := uc.userRepo.ListUsers(middlewares.GetCurrentRestaurant(r).Id, search, pageSize, (page-1)*pageSize)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.UsersPageResponse{
+		Success:  true,
+		Users:    users,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+// AddMember gives a member of another restaurant of the organization a
+// role in the active restaurant. Everyone else is invited, so the answer
+// does not tell whether an account exists elsewhere.
+func (uc *UserController) AddMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.AddMemberRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if !models.IsValidRole(req.Role) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "invalid role",
+		})
+		return
+	}
+
+	actor := middlewares.GetCurrentUser(r)
+	restaurant := middlewares.GetCurrentRestaurant(r)
+
+	if !canGrantRole(w, uc.roleRepo, actor, models.Role(req.Role)) {
+		return
+	}
+
+	user, err := uc.userRepo.GetUserByEmail(strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	inOrganization := false
+	if user != nil && user.IsStaff() {
+		inOrganization, err = uc.restaurantRepo.HasMembershipIn(user.Id, restaurant.OrganizationId)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+	}
+
+	if !inOrganization {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "No member of this organization uses this email, send an invitation instead",
+		})
+		return
+	}
+
+	added, err := uc.restaurantRepo.AddMember(&models.Membership{
+		UserId:       user.Id,
+		RestaurantId: restaurant.Id,
+		Role:         models.Role(req.Role),
+	})
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error adding member",
+		})
+		return
+	}
+
+	if !added {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "User is already a member of this restaurant",
+		})
+		return
+	}
+
+	uc.auditService.Record(r, actor, models.AuditActionMemberAdded, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"role": req.Role,
+	})
+
+	user.Role = models.Role(req.Role)
+
+	utils.WriteJSON(w, http.StatusCreated, models.UserResponse{
+		Success: true,
+		User:    user,
+	})
+}
+
+func (uc *UserController) GetUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := uc.findUser(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.UserResponse{
+		Success: true,
+		User:    user,
+	})
+}
+
+func (uc *UserController) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid JSON format",
+		})
+		return
+	}
+
+	if !models.IsValidRole(req.Role) {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "invalid role",
+		})
+		return
+	}
+
+	user, ok := uc.findOtherUser(w, r)
+	if !ok {
+		return
+	}
+
+	actor := middlewares.GetCurrentUser(r)
+	if (user.Role == models.RoleOwner || models.Role(req.Role) == models.RoleOwner) && actor.Role != models.RoleOwner {
+		utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   "Only owners can grant or revoke the owner role",
+		})
+		return
+	}
+
+	if err := uc.userRepo.UpdateRole(middlewares.GetCurrentRestaurant(r).Id, user.Id, models.Role(req.Role)); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error updating role",
+		})
+		return
+	}
+
+	uc.auditService.Record(r, actor, models.AuditActionUserRoleChanged, models.AuditTargetUser, user.Id.String(), models.AuditDiff{
+		"role": {Old: user.Role, New: req.Role},
+	})
+
+	user.Role = models.Role(req.Role)
+
+	utils.WriteJSON(w, http.StatusOK, models.UserResponse{
+		Success: true,
+		User:    user,
+	})
+}
+
+func (uc *UserController) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	uc.setUserActive(w, r, false)
+}
+
+func (uc *UserController) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	uc.setUserActive(w, r, true)
+}
+
+// DeleteUser removes the user from the active restaurant. Accounts left
+// without any restaurant are soft deleted and signed out everywhere.
+func (uc *UserController) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := uc.findOtherUser(w, r)
+	if !ok || !uc.canManage(w, r, user) {
+		return
+	}
+
+	actor := middlewares.GetCurrentUser(r)
+
+	if _, err := uc.restaurantRepo.RemoveMember(middlewares.GetCurrentRestaurant(r).Id, user.Id); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error removing member",
+		})
+		return
+	}
+
+	uc.auditService.Record(r, actor, models.AuditActionMemberRemoved, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"role": user.Role,
+	})
+
+	remaining, err := uc.restaurantRepo.GetUserRestaurants(user.Id)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if len(remaining) > 0 {
+		utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+			Success: true,
+			Message: "User removed from this restaurant",
+		})
+		return
+	}
+
+	if err := uc.userRepo.SoftDeleteUser(user.Id); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error deleting user",
+		})
+		return
+	}
+
+	uc.auditService.Record(r, actor, models.AuditActionUserDeleted, models.AuditTargetUser, user.Id.String(), map[string]any{
+		"email": user.Email,
+	})
+
+	if _, err := uc.sessionService.RevokeAllUserSessions(user.Id); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error ending user sessions",
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, models.MessageResponse{
+		Success: true,
+		Message: "User deleted",
+	})
+}
+
+func (uc *UserController) setUserActive(w http.ResponseWriter, r *http.Request, active bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := uc.findOtherUser(w, r)
+	if !ok || !uc.canManage(w, r, user) {
+		return
+	}
+
+	// Deactivation locks the whole account, which is not this
+	// organization's call when the user also works elsewhere.
+	elsewhere, err := uc.restaurantRepo.HasMembershipOutside(user.Id, middlewares.GetCurrentRestaurant(r).OrganizationId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return
+	}
+
+	if elsewhere {
+		utils.WriteJSON(w, http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   "User also works for another organization, remove them from this restaurant instead",
+		})
+		return
+	}
+
+	if err := uc.userRepo.SetActive(user.Id, active); err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Error updating user status",
+		})
+		return
+	}
+
+	action := models.AuditActionUserReactivated
+	if !active {
+		action = models.AuditActionUserDeactivated
+	}
+
+	uc.auditService.Record(r, middlewares.GetCurrentUser(r), action, models.AuditTargetUser, user.Id.String(), models.AuditDiff{
+		"is_active": {Old: user.IsActive, New: active},
+	})
+
+	if !active {
+		if _, err := uc.sessionService.RevokeAllUserSessions(user.Id); err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Error ending user sessions",
+			})
+			return
+		}
+	}
+
+	user.IsActive = active
+
+	utils.WriteJSON(w, http.StatusOK, models.UserResponse{
+		Success: true,
+		User:    user,
+	})
+}
+
+func (uc *UserController) findUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	userId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid user id",
+		})
+		return nil, false
+	}
+
+	user, err := uc.userRepo.GetRestaurantUser(middlewares.GetCurrentRestaurant(r).Id, userId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+
+	if user == nil {
+		utils.WriteJSON(w, http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   "User not found",
+		})
+		return nil, false
+	}
+
+	return user, true
+}
+
+// findOtherUser is findUser for actions nobody may apply to themselves.
+func (uc *UserController) findOtherUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	user, ok := uc.findUser(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	if user.Id == middlewares.GetCurrentUser(r).Id {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "You cannot perform this action on your own account",
+		})
+		return nil, false
+	}
+
+	return user, true
+}
+
+// canManage keeps managers from deactivating or deleting owners.
+func (uc *UserController) canManage(w http.ResponseWriter, r *http.Request, user *models.User) bool {
+	if user.Role == models.RoleOwner && middlewares.GetCurrentUser(r).Role != models.RoleOwner {
+		utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   "Only owners can manage owner accounts",
+		})
+		return false
+	}
+
+	return true
+}
+
+// canGrantRole applies the rules of UpdateUserRole to staff joining with a
+// role: only owners hand out the owner role, and the manager role needs
+// roles.manage on top of users.manage. It writes the error response when
+// the actor may not grant the role.
+func canGrantRole(w http.ResponseWriter, roleRepo *repositories.RoleRepository, actor *models.User, role models.Role) bool {
+	if role == models.RoleOwner && actor.Role != models.RoleOwner {
+		utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   "Only owners can grant the owner role",
+		})
+		return false
+	}
+
+	if role != models.RoleManager {
+		return true
+	}
+
+	allowed, err := roleRepo.RoleHasPermission(actor.Role, models.PermissionRolesManage)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return false
+	}
+
+	if !allowed {
+		utils.WriteJSON(w, http.StatusForbidden, models.ForbiddenResponse{
+			Success:            false,
+			Error:              "You do not have permission to grant the manager role",
+			Code:               "insufficient_permission",
+			Role:               actor.Role,
+			RequiredPermission: models.PermissionRolesManage,
+		})
+		return false
+	}
+
+	return true
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(name) < 2 {
+		return fmt.Errorf("name must be at least 2 characters long")
+	}
+	if len(name) > 50 {
+		return fmt.Errorf("name must be no more than 50 characters long")
+	}
+
+	return nil
+}
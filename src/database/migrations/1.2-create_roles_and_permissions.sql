@@ -0,0 +1,76 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(20) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(50) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('owner', 'Restaurant owner with full access'),
+    ('manager', 'Runs the restaurant day to day'),
+    ('host', 'Seats guests and manages reservations'),
+    ('waiter', 'Takes and serves orders'),
+    ('cook', 'Prepares orders in the kitchen'),
+    ('cashier', 'Handles payments')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('users.read', 'View staff accounts'),
+    ('users.manage', 'Create, update and deactivate staff accounts'),
+    ('roles.manage', 'Assign roles to staff'),
+    ('menu.read', 'View the menu'),
+    ('menu.manage', 'Edit the menu'),
+    ('reservations.read', 'View reservations'),
+    ('reservations.manage', 'Create and edit reservations'),
+    ('orders.read', 'View orders'),
+    ('orders.manage', 'Create and edit orders'),
+    ('kitchen.manage', 'Update the preparation status of orders'),
+    ('payments.manage', 'Take payments and issue refunds'),
+    ('reports.read', 'View sales and staff reports'),
+    ('settings.manage', 'Change restaurant settings')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'owner', name FROM permissions
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('manager', 'users.read'),
+    ('manager', 'users.manage'),
+    ('manager', 'menu.read'),
+    ('manager', 'menu.manage'),
+    ('manager', 'reservations.read'),
+    ('manager', 'reservations.manage'),
+    ('manager', 'orders.read'),
+    ('manager', 'orders.manage'),
+    ('manager', 'kitchen.manage'),
+    ('manager', 'payments.manage'),
+    ('manager', 'reports.read'),
+    ('host', 'menu.read'),
+    ('host', 'reservations.read'),
+    ('host', 'reservations.manage'),
+    ('host', 'orders.read'),
+    ('waiter', 'menu.read'),
+    ('waiter', 'reservations.read'),
+    ('waiter', 'orders.read'),
+    ('waiter', 'orders.manage'),
+    ('cook', 'menu.read'),
+    ('cook', 'orders.read'),
+    ('cook', 'kitchen.manage'),
+    ('cashier', 'menu.read'),
+    ('cashier', 'orders.read'),
+    ('cashier', 'payments.manage')
+ON CONFLICT DO NOTHING;
+
+-- Existing accounts had unrestricted access, keep them as owners.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'owner' REFERENCES roles(name);
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'waiter';
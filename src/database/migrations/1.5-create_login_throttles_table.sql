@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS login_throttles (
+    scope VARCHAR(10) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    PRIMARY KEY (scope, key)
+);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    actor_email VARCHAR(255) NOT NULL DEFAULT '',
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL DEFAULT '',
+    target_id VARCHAR(100) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_type, target_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('audit.read', 'View the audit log')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('owner', 'audit.read')
+ON CONFLICT DO NOTHING;
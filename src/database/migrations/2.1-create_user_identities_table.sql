@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- Pending authorization requests, kept server side so the PKCE verifier
+-- and nonce never leave the backend.
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    state_hash VARCHAR(64) PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    return_to VARCHAR(512) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_oidc_login_states_expires_at ON oidc_login_states(expires_at);
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS restaurants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurants_organization_id ON restaurants(organization_id);
+
+CREATE TABLE IF NOT EXISTS restaurant_memberships (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL REFERENCES roles(name),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, restaurant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_memberships_restaurant_id ON restaurant_memberships(restaurant_id);
+
+-- The venue this backend served so far. Its id is the default for
+-- DEFAULT_RESTAURANT_ID, so single venue setups keep working unchanged.
+INSERT INTO organizations (id, name) VALUES
+    ('00000000-0000-0000-0000-000000000001', 'Default organization')
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO restaurants (id, organization_id, name) VALUES
+    ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0000-000000000001', 'Default restaurant')
+ON CONFLICT (id) DO NOTHING;
+
+-- Everyone keeps the role they had, now as a membership.
+INSERT INTO restaurant_memberships (user_id, restaurant_id, role)
+SELECT id, '00000000-0000-0000-0000-000000000001', role FROM users WHERE deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS restaurant_id UUID REFERENCES restaurants(id) ON DELETE CASCADE;
+UPDATE invitations SET restaurant_id = '00000000-0000-0000-0000-000000000001' WHERE restaurant_id IS NULL;
+ALTER TABLE invitations ALTER COLUMN restaurant_id SET NOT NULL;
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS restaurant_id UUID REFERENCES restaurants(id) ON DELETE CASCADE;
+UPDATE api_keys SET restaurant_id = '00000000-0000-0000-0000-000000000001' WHERE restaurant_id IS NULL;
+ALTER TABLE api_keys ALTER COLUMN restaurant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_api_keys_restaurant_id ON api_keys(restaurant_id);
+
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS restaurant_id UUID REFERENCES restaurants(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_audit_log_restaurant_id ON audit_log(restaurant_id);
@@ -0,0 +1,23 @@
+-- Staff use the dashboard, customers order and book online, guests are
+-- customers who only gave a name for one visit.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS account_type VARCHAR(20) NOT NULL DEFAULT 'staff';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(20) UNIQUE;
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_account_type_check;
+ALTER TABLE users ADD CONSTRAINT users_account_type_check CHECK (account_type IN ('staff', 'customer', 'guest'));
+
+-- Staff sign in with their email, customers with their email or phone.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_login_check;
+ALTER TABLE users ADD CONSTRAINT users_login_check CHECK (
+    account_type = 'guest'
+    OR (account_type = 'staff' AND email IS NOT NULL)
+    OR (account_type = 'customer' AND (email IS NOT NULL OR phone IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_account_type ON users(account_type);
+
+-- users.role must reference a role, this one grants nothing.
+INSERT INTO roles (name, description) VALUES
+    ('customer', 'Orders and books online, no dashboard access')
+ON CONFLICT (name) DO NOTHING;
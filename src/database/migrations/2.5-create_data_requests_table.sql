@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS data_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reason VARCHAR(1000) NOT NULL DEFAULT '',
+    note VARCHAR(1000) NOT NULL DEFAULT '',
+    processed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_requests_status ON data_requests(status, created_at);
+
+-- One open request of each type per user.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_requests_pending ON data_requests(user_id, type) WHERE status = 'pending';
+
+INSERT INTO permissions (name, description) VALUES
+    ('privacy.manage', 'Process data erasure requests')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('owner', 'privacy.manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,38 @@
+-- A NULL max_selections means any number of options may be chosen.
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    is_required BOOLEAN NOT NULL DEFAULT FALSE,
+    min_selections INTEGER NOT NULL DEFAULT 0 CHECK (min_selections >= 0),
+    max_selections INTEGER CHECK (max_selections >= 1),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (max_selections IS NULL OR max_selections >= min_selections)
+);
+
+CREATE INDEX IF NOT EXISTS idx_modifier_groups_restaurant ON modifier_groups(restaurant_id);
+
+-- price_delta is in minor units and may be negative, such as a smaller size.
+CREATE TABLE IF NOT EXISTS modifier_options (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta BIGINT NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_modifier_options_group ON modifier_options(group_id, position);
+
+-- Groups are shared, one "Size" group can be attached to many items.
+CREATE TABLE IF NOT EXISTS menu_item_modifier_groups (
+    item_id UUID NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    group_id UUID NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (item_id, group_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_item_modifier_groups_group ON menu_item_modifier_groups(group_id);
@@ -0,0 +1,5 @@
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS allergens TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS dietary_tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_menu_items_allergens ON menu_items USING GIN (allergens);
+CREATE INDEX IF NOT EXISTS idx_menu_items_dietary_tags ON menu_items USING GIN (dietary_tags);
@@ -0,0 +1,56 @@
+-- Menus such as breakfast or late night group categories and limit when
+-- they can be ordered. A category in no menu can be ordered at any time.
+CREATE TABLE IF NOT EXISTS menus (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_menus_restaurant ON menus(restaurant_id, position);
+
+CREATE TABLE IF NOT EXISTS menu_categories_menus (
+    menu_id UUID NOT NULL REFERENCES menus(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES menu_categories(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (menu_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_categories_menus_category ON menu_categories_menus(category_id);
+
+-- Weekly windows in the restaurant's time zone. Times are minutes after
+-- midnight and weekday 0 is Sunday. A window ending before it starts runs
+-- past midnight into the next day.
+CREATE TABLE IF NOT EXISTS menu_windows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    menu_id UUID NOT NULL REFERENCES menus(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_minute INTEGER NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute INTEGER NOT NULL CHECK (end_minute BETWEEN 1 AND 1440),
+    CHECK (start_minute <> end_minute)
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_windows_menu ON menu_windows(menu_id, weekday, start_minute);
+
+-- Overrides replace the weekly windows on every day from start_date to
+-- end_date, for holidays or seasonal menus. Without times an available
+-- override covers the whole day.
+CREATE TABLE IF NOT EXISTS menu_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    menu_id UUID NOT NULL REFERENCES menus(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    is_available BOOLEAN NOT NULL,
+    start_minute INTEGER CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute INTEGER CHECK (end_minute BETWEEN 1 AND 1440),
+    note VARCHAR(200) NOT NULL DEFAULT '',
+    CHECK (end_date >= start_date),
+    CHECK ((start_minute IS NULL) = (end_minute IS NULL)),
+    CHECK (start_minute IS NULL OR start_minute <> end_minute)
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_overrides_menu ON menu_overrides(menu_id, start_date);
@@ -0,0 +1,17 @@
+-- Translations of menu texts, such as the name of an item in German. The
+-- entity is one of the translatable menu rows, see models.TranslatableFields.
+-- source keeps the text that was translated, so translations of texts that
+-- changed since can be found.
+CREATE TABLE IF NOT EXISTS translations (
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    value VARCHAR(1000) NOT NULL,
+    source VARCHAR(1000) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (entity_type, entity_id, field, locale)
+);
+
+CREATE INDEX IF NOT EXISTS idx_translations_restaurant_locale ON translations(restaurant_id, locale);
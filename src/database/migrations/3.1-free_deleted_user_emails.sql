@@ -0,0 +1,7 @@
+-- Soft deleted accounts keep their email and phone for history, but no
+-- longer hold them, so the same person can be invited or sign up again.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_phone_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_active ON users(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_active ON users(phone) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Set when a signed in user links a provider to their account, instead of
+-- signing in with it.
+ALTER TABLE oidc_login_states ADD COLUMN IF NOT EXISTS link_user_id UUID REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,8 @@
+-- Two-factor challenges that completed a login, so each can only be used
+-- once. Rows are only needed until the challenge would have expired.
+CREATE TABLE IF NOT EXISTS used_mfa_challenges (
+    id VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_used_mfa_challenges_expires_at ON used_mfa_challenges(expires_at);
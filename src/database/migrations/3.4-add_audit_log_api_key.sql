@@ -0,0 +1,4 @@
+-- Requests made with an API key have no user, the key stands in as the
+-- actor. The prefix stays readable after the key is deleted.
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS api_key_id UUID REFERENCES api_keys(id) ON DELETE SET NULL;
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS api_key_prefix VARCHAR(16) NOT NULL DEFAULT '';
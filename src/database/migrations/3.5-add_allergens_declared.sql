@@ -0,0 +1,8 @@
+-- An empty allergen list only means free of allergens once it has been
+-- declared, new columns default to undeclared. Items that already list
+-- allergens were declared by whoever entered them.
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS allergens_declared BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE menu_items SET allergens_declared = TRUE WHERE allergens <> '{}';
+
+ALTER TABLE modifier_options ADD COLUMN IF NOT EXISTS allergens TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE modifier_options ADD COLUMN IF NOT EXISTS allergens_declared BOOLEAN NOT NULL DEFAULT FALSE;
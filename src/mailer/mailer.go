@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"restaurant-backend/src/config"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email. Implementations must be safe for
+// concurrent use.
+type Mailer interface {
+	Send(message Message) error
+}
+
+func NewMailer(c *config.MailConfig) Mailer {
+	switch strings.ToLower(c.Driver) {
+	case "file":
+		return &FileMailer{from: c.From, dir: c.OutputDir}
+	default:
+		return &LogMailer{from: c.From}
+	}
+}
+
+// LogMailer writes messages to the application log instead of sending them.
+type LogMailer struct {
+	from string
+}
+
+func (lm *LogMailer) Send(message Message) error {
+	log.Printf("MAIL: from=%s to=%s subject=%q\n%s", lm.from, message.To, message.Subject, message.Body)
+	return nil
+}
+
+// FileMailer stores each message as an .eml file in a local directory.
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+func (fm *FileMailer) Send(message Message) error {
+	if err := os.MkdirAll(fm.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating mail directory: %v", err)
+	}
+
+	now := time.Now()
+	filename := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), uuid.New().String())
+
+	content := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n",
+		fm.from, message.To, message.Subject, now.Format(time.RFC1123Z), message.Body)
+
+	if err := os.WriteFile(filepath.Join(fm.dir, filename), []byte(content), 0o644); err != nil {
+		return fmt.Errorf("error writing mail file: %v", err)
+	}
+
+	return nil
+}
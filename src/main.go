@@ -8,7 +8,11 @@ import (
 	"restaurant-backend/src/database"
 	"restaurant-backend/src/models"
 	"restaurant-backend/src/routes"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
 	"strconv"
+	// Restaurant timezones must resolve on hosts without zoneinfo.
+	_ "time/tzdata"
 
 	"github.com/rs/cors"
 )
@@ -26,17 +30,63 @@ func main() {
 	}
 	defer database.CloseDB(db)
 
+	hasher, err := utils.NewPasswordHasher(
+		envConfig.App.PasswordHashAlgorithm,
+		envConfig.App.PasswordBcryptCost,
+		envConfig.App.PasswordArgon2MemoryKiB,
+		envConfig.App.PasswordArgon2Iterations,
+		envConfig.App.PasswordArgon2Parallelism,
+	)
+	if err != nil {
+		log.Fatal("Invalid password hashing config:", err)
+	}
+	utils.SetPasswordHasher(hasher)
+
+	if err := utils.SetTrustedProxies(envConfig.App.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies config:", err)
+	}
+
+	if err := services.LoadPasswordBlocklist(envConfig.App); err != nil {
+		log.Fatal("Invalid password blocklist config:", err)
+	}
+
+	if err := services.CheckAccessTokenSecret(envConfig.App); err != nil {
+		log.Fatal("Invalid access token config:", err)
+	}
+
+	if _, err := services.DefaultUserRole(envConfig.App); err != nil {
+		log.Fatal("Invalid default user role config:", err)
+	}
+
+	if _, err := services.PrivacyCustomerRestaurantId(envConfig.App); err != nil {
+		log.Fatal("Invalid privacy config:", err)
+	}
+
 	mux := http.NewServeMux()
 	AppContext.Mux = mux
 	fmt.Printf("Server started on %d port \n", envConfig.App.Port)
 
 	routes.AuthRoutes(&AppContext)
+	routes.InvitationRoutes(&AppContext)
+	routes.UserRoutes(&AppContext)
+	routes.AuditRoutes(&AppContext)
+	routes.OIDCRoutes(&AppContext)
+	routes.APIKeyRoutes(&AppContext)
+	routes.RestaurantRoutes(&AppContext)
+	routes.PrivacyRoutes(&AppContext)
+	routes.MenuRoutes(&AppContext)
+	routes.ModifierRoutes(&AppContext)
+	routes.ScheduleRoutes(&AppContext)
+	routes.TranslationRoutes(&AppContext)
+
+	services.NewSessionService(&AppContext).StartCleanup()
+	services.NewLoginThrottleService(&AppContext).StartCleanup()
 
 	// CORS configuration using github.com/rs/cors
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-API-Key", "X-Restaurant-Id"},
 		AllowCredentials: true,
 	})
 
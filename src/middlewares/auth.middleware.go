@@ -0,0 +1,293 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/services"
+	"restaurant-backend/src/utils"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const (
+	userContextKey    contextKey = "user"
+	sessionContextKey contextKey = "session"
+)
+
+type AuthMiddleware struct {
+	userRepo            *repositories.UserRepository
+	restaurantRepo      *repositories.RestaurantRepository
+	roleRepo            *repositories.RoleRepository
+	sessionService      *services.SessionService
+	tokenService        *services.TokenService
+	verificationService *services.EmailVerificationService
+	twoFactorService    *services.TwoFactorService
+	apiKeyService       *services.APIKeyService
+}
+
+func NewAuthMiddleware(ctx *models.AppContext) *AuthMiddleware {
+	return &AuthMiddleware{
+		userRepo:            repositories.NewUserRepository(ctx.DB),
+		restaurantRepo:      repositories.NewRestaurantRepository(ctx.DB),
+		roleRepo:            repositories.NewRoleRepository(ctx.DB),
+		sessionService:      services.NewSessionService(ctx),
+		tokenService:        services.NewTokenService(ctx),
+		verificationService: services.NewEmailVerificationService(ctx),
+		twoFactorService:    services.NewTwoFactorService(ctx),
+		apiKeyService:       services.NewAPIKeyService(ctx),
+	}
+}
+
+// RequireAuth resolves the caller from the dashboard cookie or the
+// Authorization header and rejects the request with 401 otherwise. The
+// header may carry a signed access token or a session token. Only staff
+// accounts get through, customers use the routes behind RequireCustomer.
+//
+// It also resolves the active restaurant, see resolveRestaurant, and the
+// attached user then carries their role in that restaurant.
+func (am *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, session, ok := am.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		if !user.IsStaff() {
+			utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+				Success: false,
+				Error:   "Customer accounts cannot use the dashboard",
+			})
+			return
+		}
+
+		membership, ok := am.resolveRestaurant(w, r, user)
+		if !ok {
+			return
+		}
+
+		ctx := r.Context()
+		if membership != nil {
+			user.Role = membership.Role
+			ctx = models.ContextWithRestaurant(ctx, membership.Restaurant)
+		}
+
+		next.ServeHTTP(w, r.WithContext(withUser(ctx, user, session)))
+	})
+}
+
+// RequireCustomer is RequireAuth for customer and guest accounts.
+func (am *AuthMiddleware) RequireCustomer(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, session, ok := am.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		if user.IsStaff() {
+			utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+				Success: false,
+				Error:   "Only customer accounts can use this endpoint",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withUser(r.Context(), user, session)))
+	})
+}
+
+// authenticate finds the user behind the request and writes the error
+// response when there is none.
+func (am *AuthMiddleware) authenticate(w http.ResponseWriter, r *http.Request) (*models.User, *models.Session, bool) {
+	var userId uuid.UUID
+	var session *models.Session
+
+	if cookie, err := r.Cookie(services.SessionCookieName); err == nil && cookie.Value != "" {
+		resolved, ok := am.resolveSession(w, cookie.Value)
+		if !ok {
+			return nil, nil, false
+		}
+		session, userId = resolved, resolved.UserId
+	} else if token := extractBearerToken(r); token != "" {
+		if utils.IsJWT(token) {
+			parsed, err := am.tokenService.ParseAccessToken(token)
+			if err != nil {
+				writeUnauthorized(w, "Access token is invalid or expired")
+				return nil, nil, false
+			}
+			userId = parsed
+		} else {
+			resolved, ok := am.resolveSession(w, token)
+			if !ok {
+				return nil, nil, false
+			}
+			session, userId = resolved, resolved.UserId
+		}
+	} else {
+		writeUnauthorized(w, "Authentication required")
+		return nil, nil, false
+	}
+
+	user, err := am.userRepo.GetUserById(userId.String())
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, nil, false
+	}
+	if user == nil {
+		writeUnauthorized(w, "User not found")
+		return nil, nil, false
+	}
+	if !user.IsActive {
+		utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   "Account is deactivated",
+		})
+		return nil, nil, false
+	}
+
+	return user, session, true
+}
+
+func withUser(ctx context.Context, user *models.User, session *models.Session) context.Context {
+	ctx = context.WithValue(ctx, userContextKey, user)
+	if session != nil {
+		ctx = context.WithValue(ctx, sessionContextKey, session)
+	}
+	return ctx
+}
+
+func (am *AuthMiddleware) resolveSession(w http.ResponseWriter, token string) (*models.Session, bool) {
+	session, err := am.sessionService.ResolveSession(token)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+	if session == nil {
+		writeUnauthorized(w, "Session is invalid or expired")
+		return nil, false
+	}
+
+	return session, true
+}
+
+// resolveRestaurant picks the restaurant from the {restaurantId} path
+// segment, then the X-Restaurant-Id header, and otherwise falls back to the
+// user's only restaurant. Users with several restaurants and no selection
+// get none, which routes guarded by a permission reject.
+func (am *AuthMiddleware) resolveRestaurant(w http.ResponseWriter, r *http.Request, user *models.User) (*models.RestaurantMembership, bool) {
+	restaurantId, requested, ok := requestedRestaurant(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	if !requested {
+		memberships, err := am.restaurantRepo.GetUserRestaurants(user.Id)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return nil, false
+		}
+
+		if len(memberships) == 1 {
+			return memberships[0], true
+		}
+		return nil, true
+	}
+
+	membership, err := am.restaurantRepo.GetUserRestaurant(user.Id, restaurantId)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   "Database error",
+		})
+		return nil, false
+	}
+	if membership == nil {
+		utils.WriteJSON(w, http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   "You are not a member of this restaurant",
+		})
+		return nil, false
+	}
+
+	return membership, true
+}
+
+// requestedRestaurant reports the restaurant the caller selected, if any.
+func requestedRestaurant(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool, bool) {
+	value := r.PathValue("restaurantId")
+	if value == "" {
+		value = strings.TrimSpace(r.Header.Get(models.RestaurantHeader))
+	}
+	if value == "" {
+		return uuid.Nil, false, true
+	}
+
+	restaurantId, err := uuid.Parse(value)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   "Invalid restaurant id",
+		})
+		return uuid.Nil, false, false
+	}
+
+	return restaurantId, true, true
+}
+
+func (am *AuthMiddleware) RequireAuthFunc(next http.HandlerFunc) http.Handler {
+	return am.RequireAuth(next)
+}
+
+// GetCurrentUser returns the user attached by RequireAuth, or nil.
+func GetCurrentUser(r *http.Request) *models.User {
+	user, _ := r.Context().Value(userContextKey).(*models.User)
+	return user
+}
+
+// GetCurrentSession returns the session attached by RequireAuth, or nil
+// when the caller authenticated with an access token.
+func GetCurrentSession(r *http.Request) *models.Session {
+	session, _ := r.Context().Value(sessionContextKey).(*models.Session)
+	return session
+}
+
+// GetCurrentRestaurant returns the active restaurant, or nil when none was
+// selected and the user does not belong to exactly one.
+func GetCurrentRestaurant(r *http.Request) *models.Restaurant {
+	return models.RestaurantFromContext(r.Context())
+}
+
+// GetCurrentAPIKey returns the API key attached by RequirePermission, or
+// nil when a person is calling.
+func GetCurrentAPIKey(r *http.Request) *models.APIKey {
+	return models.APIKeyFromContext(r.Context())
+}
+
+func extractBearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+
+	return ""
+}
+
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	utils.WriteJSON(w, http.StatusUnauthorized, models.ErrorResponse{
+		Success: false,
+		Error:   message,
+	})
+}
@@ -0,0 +1,135 @@
+package middlewares
+
+import (
+	"net/http"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/utils"
+)
+
+// RequirePermission authenticates the caller and only lets the request
+// through when the caller's role grants the permission. Integrations may
+// call these routes with an API key instead, which must carry the
+// permission as a scope. Handlers then see no current user, and the active
+// restaurant is always the one the key was created for.
+func (am *AuthMiddleware) RequirePermission(permission string, next http.HandlerFunc) http.Handler {
+	withUser := am.requireRolePermission(permission, next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plain := r.Header.Get(models.APIKeyHeader)
+		if plain == "" {
+			withUser.ServeHTTP(w, r)
+			return
+		}
+
+		key, err := am.apiKeyService.Resolve(plain)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+		if key == nil {
+			writeUnauthorized(w, "API key is invalid, expired or revoked")
+			return
+		}
+
+		if !key.HasScope(permission) {
+			utils.WriteJSON(w, http.StatusForbidden, models.ForbiddenResponse{
+				Success:            false,
+				Error:              "API key is missing the required scope",
+				Code:               "insufficient_scope",
+				RequiredPermission: permission,
+			})
+			return
+		}
+
+		restaurantId, requested, ok := requestedRestaurant(w, r)
+		if !ok {
+			return
+		}
+		if requested && restaurantId != key.RestaurantId {
+			utils.WriteJSON(w, http.StatusForbidden, models.ForbiddenResponse{
+				Success:            false,
+				Error:              "API key belongs to another restaurant",
+				Code:               "restaurant_access_denied",
+				RequiredPermission: permission,
+			})
+			return
+		}
+
+		restaurant, err := am.restaurantRepo.GetRestaurant(key.RestaurantId)
+		if err != nil || restaurant == nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+
+		ctx := models.ContextWithAPIKey(r.Context(), key)
+		ctx = models.ContextWithRestaurant(ctx, restaurant)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (am *AuthMiddleware) requireRolePermission(permission string, next http.HandlerFunc) http.Handler {
+	return am.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := GetCurrentUser(r)
+
+		if GetCurrentRestaurant(r) == nil {
+			utils.WriteJSON(w, http.StatusForbidden, models.ForbiddenResponse{
+				Success:            false,
+				Error:              "Select a restaurant with the " + models.RestaurantHeader + " header",
+				Code:               "restaurant_required",
+				RequiredPermission: permission,
+			})
+			return
+		}
+
+		if am.verificationService.IsBlocked(user, permission) {
+			utils.WriteJSON(w, http.StatusForbidden, models.ForbiddenResponse{
+				Success:            false,
+				Error:              "Please verify your email address to use this feature",
+				Code:               "email_not_verified",
+				Role:               user.Role,
+				RequiredPermission: permission,
+			})
+			return
+		}
+
+		if am.twoFactorService.IsRequired(user) && !user.IsTwoFactorEnabled() {
+			utils.WriteJSON(w, http.StatusForbidden, models.ForbiddenResponse{
+				Success:            false,
+				Error:              "Two-factor authentication is mandatory for your role, please enable it first",
+				Code:               "mfa_enrollment_required",
+				Role:               user.Role,
+				RequiredPermission: permission,
+			})
+			return
+		}
+
+		allowed, err := am.roleRepo.RoleHasPermission(user.Role, permission)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error:   "Database error",
+			})
+			return
+		}
+
+		if !allowed {
+			utils.WriteJSON(w, http.StatusForbidden, models.ForbiddenResponse{
+				Success:            false,
+				Error:              "You do not have permission to perform this action",
+				Code:               "insufficient_permission",
+				Role:               user.Role,
+				RequiredPermission: permission,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}))
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader carries API keys, kept apart from Authorization so keys are
+// never mistaken for a person's session or access token.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyScopes are the permissions an API key may be granted. Staff and
+// role management stay with people.
+var APIKeyScopes = []string{
+	PermissionMenuRead,
+	PermissionMenuManage,
+	PermissionReservationsRead,
+	PermissionReservationsManage,
+	PermissionOrdersRead,
+	PermissionOrdersManage,
+	PermissionReportsRead,
+}
+
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKey struct {
+	Id           uuid.UUID  `json:"id"`
+	RestaurantId uuid.UUID  `json:"restaurant_id"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`
+	KeyHash      string     `json:"-"`
+	Scopes       []string   `json:"scopes"`
+	CreatedBy    *uuid.UUID `json:"created_by"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeyCreatedResponse is the only response that ever contains the key.
+type APIKeyCreatedResponse struct {
+	Success bool    `json:"success"`
+	Message string  `json:"message"`
+	Key     string  `json:"key"`
+	APIKey  *APIKey `json:"api_key"`
+}
+
+type APIKeysResponse struct {
+	Success bool      `json:"success"`
+	APIKeys []*APIKey `json:"api_keys"`
+}
+
+type apiKeyContextKey struct{}
+
+// ContextWithAPIKey attaches the API key a request was made with. It lives
+// here rather than in middlewares so services can read it too, see
+// ContextWithRestaurant.
+func ContextWithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// APIKeyFromContext returns the API key of the request, or nil.
+func APIKeyFromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionUserRegistered                 = "user.registered"
+	AuditActionCustomerRegistered             = "customer.registered"
+	AuditActionUserProfileUpdated             = "user.profile_updated"
+	AuditActionUserPasswordChanged            = "user.password_changed"
+	AuditActionUserPasswordReset              = "user.password_reset"
+	AuditActionUserEmailChanged               = "user.email_change_requested"
+	AuditActionUserRoleChanged                = "user.role_changed"
+	AuditActionUserDeactivated                = "user.deactivated"
+	AuditActionUserReactivated                = "user.reactivated"
+	AuditActionUserDeleted                    = "user.deleted"
+	AuditActionUserUnlocked                   = "user.unlocked"
+	AuditActionLoginSucceeded                 = "auth.login_succeeded"
+	AuditActionLoginFailed                    = "auth.login_failed"
+	AuditActionTwoFactorEnabled               = "auth.two_factor_enabled"
+	AuditActionTwoFactorDisabled              = "auth.two_factor_disabled"
+	AuditActionIdentityLinked                 = "auth.identity_linked"
+	AuditActionInvitationCreated              = "invitation.created"
+	AuditActionInvitationRevoked              = "invitation.revoked"
+	AuditActionInvitationAccepted             = "invitation.accepted"
+	AuditActionAPIKeyCreated                  = "api_key.created"
+	AuditActionAPIKeyRevoked                  = "api_key.revoked"
+	AuditActionMemberAdded                    = "restaurant.member_added"
+	AuditActionMemberRemoved                  = "restaurant.member_removed"
+	AuditActionRestaurantCreated              = "restaurant.created"
+	AuditActionOrganizationCreated            = "organization.created"
+	AuditActionDataExported                   = "privacy.data_exported"
+	AuditActionErasureRequested               = "privacy.erasure_requested"
+	AuditActionErasureCompleted               = "privacy.erasure_completed"
+	AuditActionErasureRejected                = "privacy.erasure_rejected"
+	AuditActionMenuCategoryCreated            = "menu.category_created"
+	AuditActionMenuCategoryUpdated            = "menu.category_updated"
+	AuditActionMenuCategoryDeleted            = "menu.category_deleted"
+	AuditActionMenuItemCreated                = "menu.item_created"
+	AuditActionMenuItemUpdated                = "menu.item_updated"
+	AuditActionMenuItemDeleted                = "menu.item_deleted"
+	AuditActionMenuReordered                  = "menu.reordered"
+	AuditActionMenuItemModifiersSet           = "menu.item_modifiers_set"
+	AuditActionMenuItemAllergensChanged       = "menu.item_allergens_changed"
+	AuditActionModifierGroupCreated           = "menu.modifier_group_created"
+	AuditActionModifierGroupUpdated           = "menu.modifier_group_updated"
+	AuditActionModifierGroupDeleted           = "menu.modifier_group_deleted"
+	AuditActionModifierOptionCreated          = "menu.modifier_option_created"
+	AuditActionModifierOptionUpdated          = "menu.modifier_option_updated"
+	AuditActionModifierOptionDeleted          = "menu.modifier_option_deleted"
+	AuditActionModifierOptionAllergensChanged = "menu.modifier_option_allergens_changed"
+	AuditActionScheduledMenuCreated           = "menu.menu_created"
+	AuditActionScheduledMenuUpdated           = "menu.menu_updated"
+	AuditActionScheduledMenuDeleted           = "menu.menu_deleted"
+	AuditActionMenuCategoriesSet              = "menu.menu_categories_set"
+	AuditActionMenuScheduleSet                = "menu.menu_schedule_set"
+	AuditActionTranslationSet                 = "menu.translation_set"
+	AuditActionTranslationDeleted             = "menu.translation_deleted"
+)
+
+const (
+	AuditTargetUser           = "user"
+	AuditTargetInvitation     = "invitation"
+	AuditTargetAPIKey         = "api_key"
+	AuditTargetRestaurant     = "restaurant"
+	AuditTargetDataRequest    = "data_request"
+	AuditTargetMenuCategory   = "menu_category"
+	AuditTargetMenuItem       = "menu_item"
+	AuditTargetModifierGroup  = "modifier_group"
+	AuditTargetModifierOption = "modifier_option"
+	AuditTargetMenu           = "menu"
+)
+
+// AuditEntry is one row of the audit log. Changes holds a field diff for
+// updates or free form details for other events.
+type AuditEntry struct {
+	Id           uuid.UUID       `json:"id"`
+	RestaurantId *uuid.UUID      `json:"restaurant_id"`
+	ActorId      *uuid.UUID      `json:"actor_id"`
+	ActorEmail   string          `json:"actor_email"`
+	APIKeyId     *uuid.UUID      `json:"api_key_id"`
+	APIKeyPrefix string          `json:"api_key_prefix"`
+	Action       string          `json:"action"`
+	TargetType   string          `json:"target_type"`
+	TargetId     string          `json:"target_id"`
+	IPAddress    string          `json:"ip_address"`
+	UserAgent    string          `json:"user_agent"`
+	Changes      json.RawMessage `json:"changes"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+type AuditChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// AuditDiff maps a field name to its old and new value.
+type AuditDiff map[string]AuditChange
+
+// AuditLogFilter always carries the restaurant. Entries recorded outside
+// a restaurant, such as logins, are included when the actor is a member.
+type AuditLogFilter struct {
+	RestaurantId uuid.UUID
+	ActorId      *uuid.UUID
+	Action       string
+	TargetType   string
+	TargetId     string
+	From         *time.Time
+	To           *time.Time
+}
+
+type AuditLogResponse struct {
+	Success  bool          `json:"success"`
+	Entries  []*AuditEntry `json:"entries"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+	Total    int           `json:"total"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links an account at an external OpenID Connect provider
+// to a user.
+type UserIdentity struct {
+	Id          uuid.UUID  `json:"id"`
+	UserId      uuid.UUID  `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"-"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+type OIDCLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ReturnTo     string
+	// LinkUserId is the signed in user who is linking the provider, nil
+	// for a sign in.
+	LinkUserId *uuid.UUID
+	ExpiresAt  time.Time
+}
+
+type OIDCProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	LoginURL    string `json:"login_url"`
+}
+
+type OIDCProvidersResponse struct {
+	Success   bool            `json:"success"`
+	Providers []*OIDCProvider `json:"providers"`
+}
+
+type UserIdentitiesResponse struct {
+	Success    bool            `json:"success"`
+	Identities []*UserIdentity `json:"identities"`
+}
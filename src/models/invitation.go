@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Invitation struct {
+	Id           uuid.UUID  `json:"id"`
+	RestaurantId uuid.UUID  `json:"restaurant_id"`
+	Email        string     `json:"email"`
+	Role         Role       `json:"role"`
+	TokenHash    string     `json:"-"`
+	InvitedBy    *uuid.UUID `json:"invited_by"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Name     string `json:"name" validate:"required,min=2,max=50"`
+	Password string `json:"password" validate:"required"`
+}
+
+type JoinInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type InvitationResponse struct {
+	Success    bool        `json:"success"`
+	Message    string      `json:"message"`
+	Invitation *Invitation `json:"invitation"`
+}
+
+type InvitationsResponse struct {
+	Success     bool          `json:"success"`
+	Invitations []*Invitation `json:"invitations"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	ThrottleScopeEmail = "email"
+	ThrottleScopeIP    = "ip"
+	// Counts guest sessions started from an IP.
+	ThrottleScopeGuest = "guest"
+)
+
+type LoginThrottle struct {
+	Scope        string
+	Key          string
+	FailedCount  int
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
+
+// ThrottleKey is one counter a login attempt is checked against.
+type ThrottleKey struct {
+	Scope string
+	Key   string
+}
+
+// UnlockAccountRequest lifts the lockout of the email, and of the IP
+// address the attempts came from when one is given.
+type UnlockAccountRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	IP    string `json:"ip"`
+}
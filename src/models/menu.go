@@ -0,0 +1,156 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The 14 allergens EU law requires restaurants to declare.
+const (
+	AllergenCelery      = "celery"
+	AllergenGluten      = "gluten"
+	AllergenCrustaceans = "crustaceans"
+	AllergenEggs        = "eggs"
+	AllergenFish        = "fish"
+	AllergenLupin       = "lupin"
+	AllergenMilk        = "milk"
+	AllergenMolluscs    = "molluscs"
+	AllergenMustard     = "mustard"
+	AllergenNuts        = "nuts"
+	AllergenPeanuts     = "peanuts"
+	AllergenSesame      = "sesame"
+	AllergenSoya        = "soya"
+	AllergenSulphites   = "sulphites"
+)
+
+var AllAllergens = []string{
+	AllergenCelery, AllergenGluten, AllergenCrustaceans, AllergenEggs, AllergenFish, AllergenLupin, AllergenMilk,
+	AllergenMolluscs, AllergenMustard, AllergenNuts, AllergenPeanuts, AllergenSesame, AllergenSoya, AllergenSulphites,
+}
+
+const (
+	DietaryTagVegan      = "vegan"
+	DietaryTagVegetarian = "vegetarian"
+	DietaryTagHalal      = "halal"
+	DietaryTagGlutenFree = "gluten_free"
+)
+
+var AllDietaryTags = []string{DietaryTagVegan, DietaryTagVegetarian, DietaryTagHalal, DietaryTagGlutenFree}
+
+type MenuCategory struct {
+	Id           uuid.UUID   `json:"id"`
+	RestaurantId uuid.UUID   `json:"restaurant_id"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description"`
+	Position     int         `json:"position"`
+	IsAvailable  bool        `json:"is_available"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	Items        []*MenuItem `json:"items,omitempty"`
+}
+
+// MenuItem prices are integers in the minor unit of the currency, such as
+// cents, so no rounding ever happens. Allergens are only known once
+// AllergensDeclared is set, an undeclared item may contain any of them.
+type MenuItem struct {
+	Id                uuid.UUID `json:"id"`
+	RestaurantId      uuid.UUID `json:"restaurant_id"`
+	CategoryId        uuid.UUID `json:"category_id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	Price             int64     `json:"price"`
+	Allergens         []string  `json:"allergens"`
+	AllergensDeclared bool      `json:"allergens_declared"`
+	DietaryTags       []string  `json:"dietary_tags"`
+	Position          int       `json:"position"`
+	IsAvailable       bool      `json:"is_available"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	ModifierGroups []*ModifierGroup `json:"modifier_groups,omitempty"`
+}
+
+// MenuItemFilter narrows the item list. Nil and empty fields do not
+// filter. Items must have declared none of ExcludeAllergens and carry all
+// of DietaryTags.
+type MenuItemFilter struct {
+	RestaurantId     uuid.UUID
+	CategoryId       *uuid.UUID
+	IsAvailable      *bool
+	ExcludeAllergens []string
+	DietaryTags      []string
+}
+
+type CreateMenuCategoryRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	Position    *int   `json:"position"`
+	IsAvailable *bool  `json:"is_available"`
+}
+
+type UpdateMenuCategoryRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Position    *int    `json:"position"`
+	IsAvailable *bool   `json:"is_available"`
+}
+
+// CreateMenuItemRequest declares the allergens of the item when allergens
+// is given, an empty list declares it free of them.
+type CreateMenuItemRequest struct {
+	CategoryId  uuid.UUID `json:"category_id" validate:"required"`
+	Name        string    `json:"name" validate:"required"`
+	Description string    `json:"description"`
+	Price       *int64    `json:"price" validate:"required"`
+	Allergens   []string  `json:"allergens"`
+	DietaryTags []string  `json:"dietary_tags"`
+	Position    *int      `json:"position"`
+	IsAvailable *bool     `json:"is_available"`
+}
+
+// UpdateMenuItemRequest declares the allergens of the item when allergens
+// is given, see CreateMenuItemRequest.
+type UpdateMenuItemRequest struct {
+	CategoryId  *uuid.UUID `json:"category_id"`
+	Name        *string    `json:"name"`
+	Description *string    `json:"description"`
+	Price       *int64     `json:"price"`
+	Allergens   *[]string  `json:"allergens"`
+	DietaryTags *[]string  `json:"dietary_tags"`
+	Position    *int       `json:"position"`
+	IsAvailable *bool      `json:"is_available"`
+}
+
+// ReorderMenuRequest lists ids in their new order.
+type ReorderMenuRequest struct {
+	Ids []uuid.UUID `json:"ids" validate:"required"`
+}
+
+type MenuResponse struct {
+	Success    bool            `json:"success"`
+	Restaurant *Restaurant     `json:"restaurant,omitempty"`
+	OpenMenus  []*Menu         `json:"open_menus,omitempty"`
+	Categories []*MenuCategory `json:"categories"`
+}
+
+type MenuCategoryResponse struct {
+	Success  bool          `json:"success"`
+	Category *MenuCategory `json:"category"`
+}
+
+type MenuItemResponse struct {
+	Success bool      `json:"success"`
+	Item    *MenuItem `json:"item"`
+}
+
+type AllergensResponse struct {
+	Success     bool     `json:"success"`
+	Allergens   []string `json:"allergens"`
+	DietaryTags []string `json:"dietary_tags"`
+}
+
+type MenuItemsResponse struct {
+	Success bool        `json:"success"`
+	Items   []*MenuItem `json:"items"`
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ModifierGroup is a choice offered on menu items, such as size or extra
+// toppings. MaxSelections is nil when any number of options may be chosen.
+type ModifierGroup struct {
+	Id            uuid.UUID         `json:"id"`
+	RestaurantId  uuid.UUID         `json:"restaurant_id"`
+	Name          string            `json:"name"`
+	IsRequired    bool              `json:"is_required"`
+	MinSelections int               `json:"min_selections"`
+	MaxSelections *int              `json:"max_selections"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	Options       []*ModifierOption `json:"options"`
+}
+
+// MinimumSelections is the number of options that must be chosen. A
+// required group needs at least one even when MinSelections is zero.
+func (g *ModifierGroup) MinimumSelections() int {
+	if g.IsRequired && g.MinSelections < 1 {
+		return 1
+	}
+	return g.MinSelections
+}
+
+// ModifierOption prices are deltas in minor units added to the item price,
+// negative for cheaper choices. Allergens are the ones the option adds to
+// the item, declared as on MenuItem.
+type ModifierOption struct {
+	Id                uuid.UUID `json:"id"`
+	GroupId           uuid.UUID `json:"group_id"`
+	Name              string    `json:"name"`
+	PriceDelta        int64     `json:"price_delta"`
+	Allergens         []string  `json:"allergens"`
+	AllergensDeclared bool      `json:"allergens_declared"`
+	Position          int       `json:"position"`
+	IsAvailable       bool      `json:"is_available"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// ModifierGroupLink attaches a group to an item.
+type ModifierGroupLink struct {
+	ItemId  uuid.UUID
+	GroupId uuid.UUID
+}
+
+// Configuration rule names reported back to clients.
+const (
+	ConfigurationRuleItemUnavailable   = "item_unavailable"
+	ConfigurationRuleUnknownOption     = "unknown_option"
+	ConfigurationRuleDuplicateOption   = "duplicate_option"
+	ConfigurationRuleOptionUnavailable = "option_unavailable"
+	ConfigurationRuleMinSelections     = "min_selections"
+	ConfigurationRuleMaxSelections     = "max_selections"
+	ConfigurationRuleQuantity          = "quantity"
+	ConfigurationRuleNegativePrice     = "negative_price"
+	ConfigurationRuleOutsideMenuHours  = "outside_menu_hours"
+)
+
+type ConfigurationViolation struct {
+	Rule     string     `json:"rule"`
+	GroupId  *uuid.UUID `json:"group_id,omitempty"`
+	OptionId *uuid.UUID `json:"option_id,omitempty"`
+	Message  string     `json:"message"`
+}
+
+// PricedItem is a valid configuration of a menu item with its price worked
+// out on the server.
+type PricedItem struct {
+	ItemId     uuid.UUID         `json:"item_id"`
+	Name       string            `json:"name"`
+	Quantity   int               `json:"quantity"`
+	BasePrice  int64             `json:"base_price"`
+	Options    []*ModifierOption `json:"options"`
+	UnitPrice  int64             `json:"unit_price"`
+	TotalPrice int64             `json:"total_price"`
+}
+
+type CreateModifierGroupRequest struct {
+	Name          string                        `json:"name" validate:"required"`
+	IsRequired    bool                          `json:"is_required"`
+	MinSelections int                           `json:"min_selections"`
+	MaxSelections *int                          `json:"max_selections"`
+	Options       []CreateModifierOptionRequest `json:"options"`
+}
+
+// UpdateModifierGroupRequest sets max_selections to 0 to remove the limit.
+type UpdateModifierGroupRequest struct {
+	Name          *string `json:"name"`
+	IsRequired    *bool   `json:"is_required"`
+	MinSelections *int    `json:"min_selections"`
+	MaxSelections *int    `json:"max_selections"`
+}
+
+// CreateModifierOptionRequest declares the allergens of the option when
+// allergens is given, as for menu items.
+type CreateModifierOptionRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	PriceDelta  int64    `json:"price_delta"`
+	Allergens   []string `json:"allergens"`
+	Position    *int     `json:"position"`
+	IsAvailable *bool    `json:"is_available"`
+}
+
+type UpdateModifierOptionRequest struct {
+	Name        *string   `json:"name"`
+	PriceDelta  *int64    `json:"price_delta"`
+	Allergens   *[]string `json:"allergens"`
+	Position    *int      `json:"position"`
+	IsAvailable *bool     `json:"is_available"`
+}
+
+// SetItemModifierGroupsRequest replaces the groups of an item, in the
+// order they are offered.
+type SetItemModifierGroupsRequest struct {
+	GroupIds []uuid.UUID `json:"group_ids"`
+}
+
+type PriceItemRequest struct {
+	OptionIds []uuid.UUID `json:"option_ids"`
+	Quantity  int         `json:"quantity"`
+}
+
+type ModifierGroupResponse struct {
+	Success       bool           `json:"success"`
+	ModifierGroup *ModifierGroup `json:"modifier_group"`
+}
+
+type ModifierGroupsResponse struct {
+	Success        bool             `json:"success"`
+	ModifierGroups []*ModifierGroup `json:"modifier_groups"`
+}
+
+type ModifierOptionResponse struct {
+	Success bool            `json:"success"`
+	Option  *ModifierOption `json:"option"`
+}
+
+type PricedItemResponse struct {
+	Success bool        `json:"success"`
+	Item    *PricedItem `json:"item"`
+}
+
+type ConfigurationErrorResponse struct {
+	Success    bool                     `json:"success"`
+	Error      string                   `json:"error"`
+	Code       string                   `json:"code"`
+	Violations []ConfigurationViolation `json:"violations"`
+}
@@ -0,0 +1,36 @@
+package models
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Password policy rule names reported back to clients.
+const (
+	PasswordRuleRequired      = "required"
+	PasswordRuleMinLength     = "min_length"
+	PasswordRuleMaxLength     = "max_length"
+	PasswordRuleUppercase     = "uppercase"
+	PasswordRuleLowercase     = "lowercase"
+	PasswordRuleDigit         = "digit"
+	PasswordRuleSymbol        = "symbol"
+	PasswordRuleBlocklisted   = "blocklisted"
+	PasswordRuleContainsName  = "contains_name"
+	PasswordRuleContainsEmail = "contains_email"
+)
+
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type PasswordPolicyErrorResponse struct {
+	Success    bool                `json:"success"`
+	Error      string              `json:"error"`
+	Code       string              `json:"code"`
+	Violations []PasswordViolation `json:"violations"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DataRequestTypeErasure = "erasure"
+
+	DataRequestStatusPending   = "pending"
+	DataRequestStatusCompleted = "completed"
+	DataRequestStatusRejected  = "rejected"
+)
+
+// DataRequest is a request by a user to have their personal data erased,
+// waiting in the admin queue until someone processes it.
+type DataRequest struct {
+	Id          uuid.UUID  `json:"id"`
+	UserId      uuid.UUID  `json:"user_id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Reason      string     `json:"reason"`
+	Note        string     `json:"note"`
+	ProcessedBy *uuid.UUID `json:"processed_by"`
+	ProcessedAt *time.Time `json:"processed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// DataExport is everything stored about one user, as handed out by the
+// self-service export.
+type DataExport struct {
+	GeneratedAt  time.Time               `json:"generated_at"`
+	User         *User                   `json:"user"`
+	Restaurants  []*RestaurantMembership `json:"restaurants"`
+	Identities   []*UserIdentity         `json:"identities"`
+	Sessions     []*Session              `json:"sessions"`
+	Activity     []*AuditEntry           `json:"activity"`
+	DataRequests []*DataRequest          `json:"data_requests"`
+}
+
+type ErasureRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ProcessDataRequestRequest struct {
+	Note string `json:"note"`
+}
+
+type DataRequestResponse struct {
+	Success     bool         `json:"success"`
+	Message     string       `json:"message,omitempty"`
+	DataRequest *DataRequest `json:"data_request"`
+}
+
+type DataRequestsPageResponse struct {
+	Success      bool           `json:"success"`
+	DataRequests []*DataRequest `json:"data_requests"`
+	Page         int            `json:"page"`
+	PageSize     int            `json:"page_size"`
+	Total        int            `json:"total"`
+}
@@ -0,0 +1,11 @@
+package models
+
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+type MessageResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RestaurantHeader selects the active restaurant for routes that do not
+// carry it as a {restaurantId} path segment.
+const RestaurantHeader = "X-Restaurant-Id"
+
+type Organization struct {
+	Id        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Restaurant struct {
+	Id             uuid.UUID `json:"id"`
+	OrganizationId uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+	Timezone       string    `json:"timezone"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Membership gives a user a role in one restaurant. Permission checks use
+// the role of the membership for the active restaurant.
+type Membership struct {
+	UserId       uuid.UUID `json:"user_id"`
+	RestaurantId uuid.UUID `json:"restaurant_id"`
+	Role         Role      `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RestaurantMembership struct {
+	Restaurant *Restaurant `json:"restaurant"`
+	Role       Role        `json:"role"`
+}
+
+type CreateOrganizationRequest struct {
+	Name           string `json:"name" validate:"required"`
+	RestaurantName string `json:"restaurant_name" validate:"required"`
+	Timezone       string `json:"timezone"`
+}
+
+type CreateRestaurantRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Timezone string `json:"timezone"`
+}
+
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+type RestaurantResponse struct {
+	Success      bool          `json:"success"`
+	Organization *Organization `json:"organization,omitempty"`
+	Restaurant   *Restaurant   `json:"restaurant"`
+}
+
+type RestaurantsResponse struct {
+	Success     bool                    `json:"success"`
+	Restaurants []*RestaurantMembership `json:"restaurants"`
+}
+
+type restaurantContextKey struct{}
+
+// ContextWithRestaurant attaches the active restaurant. It lives here
+// rather than in middlewares so services can read it too.
+func ContextWithRestaurant(ctx context.Context, restaurant *Restaurant) context.Context {
+	return context.WithValue(ctx, restaurantContextKey{}, restaurant)
+}
+
+// RestaurantFromContext returns the active restaurant, or nil.
+func RestaurantFromContext(ctx context.Context) *Restaurant {
+	restaurant, _ := ctx.Value(restaurantContextKey{}).(*Restaurant)
+	return restaurant
+}
@@ -0,0 +1,59 @@
+package models
+
+type Role string
+
+const (
+	RoleOwner   Role = "owner"
+	RoleManager Role = "manager"
+	RoleHost    Role = "host"
+	RoleWaiter  Role = "waiter"
+	RoleCook    Role = "cook"
+	RoleCashier Role = "cashier"
+)
+
+// RoleCustomer is stored on customer and guest accounts. It grants no
+// permissions and is not part of AllRoles, so staff cannot be given it.
+const RoleCustomer Role = "customer"
+
+var AllRoles = []Role{RoleOwner, RoleManager, RoleHost, RoleWaiter, RoleCook, RoleCashier}
+
+func IsValidRole(role string) bool {
+	for _, r := range AllRoles {
+		if string(r) == role {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	PermissionUsersRead          = "users.read"
+	PermissionUsersManage        = "users.manage"
+	PermissionRolesManage        = "roles.manage"
+	PermissionMenuRead           = "menu.read"
+	PermissionMenuManage         = "menu.manage"
+	PermissionReservationsRead   = "reservations.read"
+	PermissionReservationsManage = "reservations.manage"
+	PermissionOrdersRead         = "orders.read"
+	PermissionOrdersManage       = "orders.manage"
+	PermissionKitchenManage      = "kitchen.manage"
+	PermissionPaymentsManage     = "payments.manage"
+	PermissionReportsRead        = "reports.read"
+	PermissionSettingsManage     = "settings.manage"
+	PermissionAuditRead          = "audit.read"
+	PermissionPrivacyManage      = "privacy.manage"
+)
+
+type ForbiddenResponse struct {
+	Success            bool   `json:"success"`
+	Error              string `json:"error"`
+	Code               string `json:"code"`
+	Role               Role   `json:"role,omitempty"`
+	RequiredPermission string `json:"required_permission"`
+}
+
+type PermissionsResponse struct {
+	Success     bool     `json:"success"`
+	Role        Role     `json:"role"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClockTime is a time of day in minutes after midnight, written as "HH:MM".
+// "24:00" is allowed as the end of a window that runs until midnight.
+type ClockTime int
+
+const MinutesPerDay ClockTime = 24 * 60
+
+func (c ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", c/60, c%60)
+}
+
+func (c ClockTime) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *ClockTime) UnmarshalText(text []byte) error {
+	if len(text) != 5 || text[2] != ':' {
+		return fmt.Errorf("time must look like 18:30")
+	}
+
+	digits := [4]int{}
+	for i, at := range []int{0, 1, 3, 4} {
+		if text[at] < '0' || text[at] > '9' {
+			return fmt.Errorf("time must look like 18:30")
+		}
+		digits[i] = int(text[at] - '0')
+	}
+
+	hours, minutes := digits[0]*10+digits[1], digits[2]*10+digits[3]
+	if minutes > 59 || hours*60+minutes > int(MinutesPerDay) {
+		return fmt.Errorf("time must be between 00:00 and 24:00")
+	}
+
+	*c = ClockTime(hours*60 + minutes)
+	return nil
+}
+
+// ClockTimeOf returns the time of day of t in t's location.
+func ClockTimeOf(t time.Time) ClockTime {
+	return ClockTime(t.Hour()*60 + t.Minute())
+}
+
+// Menu groups categories, like breakfast or dinner, and limits when they
+// can be ordered. A category may belong to several menus, and is orderable
+// while any of them is open. Categories in no menu are always orderable.
+type Menu struct {
+	Id           uuid.UUID       `json:"id"`
+	RestaurantId uuid.UUID       `json:"restaurant_id"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	Position     int             `json:"position"`
+	IsAvailable  bool            `json:"is_available"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	CategoryIds  []uuid.UUID     `json:"category_ids"`
+	Windows      []*MenuWindow   `json:"windows"`
+	Overrides    []*MenuOverride `json:"overrides"`
+}
+
+// MenuWindow opens a menu every week in the restaurant's time zone.
+// Weekday 0 is Sunday, as in time.Weekday. A window that ends before it
+// starts runs past midnight, so Friday 22:00 to 02:00 ends early Saturday.
+type MenuWindow struct {
+	Weekday int       `json:"weekday"`
+	Start   ClockTime `json:"start"`
+	End     ClockTime `json:"end"`
+}
+
+// MenuOverride replaces the weekly windows on every day from StartDate to
+// EndDate, both included and written as 2006-01-02. An unavailable override
+// closes the menu, for a holiday. An available one opens it between Start
+// and End, or all day when they are nil, for a seasonal menu.
+type MenuOverride struct {
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	IsAvailable bool       `json:"is_available"`
+	Start       *ClockTime `json:"start,omitempty"`
+	End         *ClockTime `json:"end,omitempty"`
+	Note        string     `json:"note"`
+}
+
+type CreateScheduledMenuRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	Position    *int   `json:"position"`
+	IsAvailable *bool  `json:"is_available"`
+}
+
+type UpdateScheduledMenuRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Position    *int    `json:"position"`
+	IsAvailable *bool   `json:"is_available"`
+}
+
+// SetMenuCategoriesRequest lists the categories of a menu in display order.
+type SetMenuCategoriesRequest struct {
+	CategoryIds []uuid.UUID `json:"category_ids" validate:"required"`
+}
+
+// SetMenuScheduleRequest replaces all windows and overrides of a menu.
+type SetMenuScheduleRequest struct {
+	Windows   []*MenuWindow   `json:"windows"`
+	Overrides []*MenuOverride `json:"overrides"`
+}
+
+type ScheduledMenuResponse struct {
+	Success bool  `json:"success"`
+	Menu    *Menu `json:"menu"`
+}
+
+type ScheduledMenusResponse struct {
+	Success bool    `json:"success"`
+	Menus   []*Menu `json:"menus"`
+}
+
+// OrderableResponse answers what can be ordered at a point in time. At is
+// given in the restaurant's time zone.
+type OrderableResponse struct {
+	Success    bool            `json:"success"`
+	At         time.Time       `json:"at"`
+	Timezone   string          `json:"timezone"`
+	OpenMenus  []*Menu         `json:"open_menus"`
+	Categories []*MenuCategory `json:"categories"`
+}
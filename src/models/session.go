@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	Id         uuid.UUID `json:"id"`
+	UserId     uuid.UUID `json:"user_id"`
+	TokenHash  string    `json:"-"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type SessionView struct {
+	*Session
+	Device  string `json:"device"`
+	Current bool   `json:"current"`
+}
+
+type SessionsResponse struct {
+	Success  bool           `json:"success"`
+	Sessions []*SessionView `json:"sessions"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	GrantTypePassword     = "password"
+	GrantTypeRefreshToken = "refresh_token"
+	GrantTypeMFA          = "mfa"
+)
+
+type RefreshToken struct {
+	Id        uuid.UUID
+	UserId    uuid.UUID
+	FamilyId  uuid.UUID
+	TokenHash string
+	UserAgent string
+	IPAddress string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+type AccessTokenClaims struct {
+	Subject   string `json:"sub"`
+	TokenId   string `json:"jti"`
+	Type      string `json:"typ"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type TokenRequest struct {
+	GrantType    string `json:"grant_type" validate:"required"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	RefreshToken string `json:"refresh_token"`
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type RevokeTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type TokenResponse struct {
+	Success      bool   `json:"success"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	User         *User  `json:"user,omitempty"`
+}
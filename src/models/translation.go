@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity types that can be translated. They match the audit targets of the
+// same rows.
+const (
+	TranslationEntityMenuCategory   = AuditTargetMenuCategory
+	TranslationEntityMenuItem       = AuditTargetMenuItem
+	TranslationEntityModifierGroup  = AuditTargetModifierGroup
+	TranslationEntityModifierOption = AuditTargetModifierOption
+	TranslationEntityMenu           = AuditTargetMenu
+)
+
+// TranslatableFields lists the fields of each entity type that take
+// translations.
+var TranslatableFields = map[string][]string{
+	TranslationEntityMenuCategory:   {"name", "description"},
+	TranslationEntityMenuItem:       {"name", "description"},
+	TranslationEntityModifierGroup:  {"name"},
+	TranslationEntityModifierOption: {"name"},
+	TranslationEntityMenu:           {"name", "description"},
+}
+
+// Translation is one field of one entity in one locale. Source is the text
+// it was translated from.
+type Translation struct {
+	EntityType string    `json:"entity_type"`
+	EntityId   uuid.UUID `json:"entity_id"`
+	Field      string    `json:"field"`
+	Locale     string    `json:"locale"`
+	Value      string    `json:"value"`
+	Source     string    `json:"source"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// MissingTranslation is a text without a translation in the locale, or
+// with one made before the text changed, which is then Outdated.
+type MissingTranslation struct {
+	EntityType string    `json:"entity_type"`
+	EntityId   uuid.UUID `json:"entity_id"`
+	Field      string    `json:"field"`
+	Locale     string    `json:"locale"`
+	Source     string    `json:"source"`
+	Outdated   bool      `json:"outdated"`
+}
+
+// TranslationFilter narrows the translation list. Empty fields do not
+// filter.
+type TranslationFilter struct {
+	RestaurantId uuid.UUID
+	EntityType   string
+	EntityId     *uuid.UUID
+	Locale       string
+}
+
+type SetTranslationRequest struct {
+	Value string `json:"value" validate:"required"`
+}
+
+type TranslationResponse struct {
+	Success     bool         `json:"success"`
+	Translation *Translation `json:"translation"`
+}
+
+type TranslationsResponse struct {
+	Success      bool           `json:"success"`
+	Translations []*Translation `json:"translations"`
+}
+
+// MissingTranslationsResponse counts the missing strings per locale next
+// to the list.
+type MissingTranslationsResponse struct {
+	Success bool                  `json:"success"`
+	Counts  map[string]int        `json:"counts"`
+	Missing []*MissingTranslation `json:"missing"`
+}
@@ -0,0 +1,45 @@
+package models
+
+type MFAChallengeClaims struct {
+	Id        string `json:"jti"`
+	Subject   string `json:"sub"`
+	Type      string `json:"typ"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type TwoFactorSetupResponse struct {
+	Success         bool   `json:"success"`
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	Success       bool     `json:"success"`
+	Message       string   `json:"message"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFAChallengeResponse struct {
+	Success     bool   `json:"success"`
+	Message     string `json:"message"`
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+// LoginTwoFactorRequest takes the challenge from the mfa_token field, or
+// from the challenge cookie after a provider sign in.
+type LoginTwoFactorRequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
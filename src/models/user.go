@@ -6,19 +6,47 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	AccountTypeStaff    = "staff"
+	AccountTypeCustomer = "customer"
+	AccountTypeGuest    = "guest"
+)
+
 type User struct {
-	Id        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Password  string    `json:"-"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id              uuid.UUID  `json:"id"`
+	Email           string     `json:"email"`
+	Phone           string     `json:"phone,omitempty"`
+	AccountType     string     `json:"account_type"`
+	Name            string     `json:"name"`
+	Password        string     `json:"-"`
+	Role            Role       `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	TOTPSecret      string     `json:"-"`
+	TOTPEnabledAt   *time.Time `json:"two_factor_enabled_at"`
+	TOTPLastStep    int64      `json:"-"`
+	IsActive        bool       `json:"is_active"`
+	DeletedAt       *time.Time `json:"-"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// IsStaff reports whether the account may use the dashboard.
+func (u *User) IsStaff() bool {
+	return u.AccountType == AccountTypeStaff
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
 }
 
 type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=2,max=50"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required"`
 }
 
 type RegisterResponse struct {
@@ -28,9 +56,27 @@ type RegisterResponse struct {
 	Error   string `json:"error,omitempty"`
 }
 
+// RegisterCustomerRequest needs an email, a phone number or both.
+type RegisterCustomerRequest struct {
+	Name     string `json:"name" validate:"required,min=2,max=50"`
+	Email    string `json:"email" validate:"omitempty,email"`
+	Phone    string `json:"phone"`
+	Password string `json:"password" validate:"required"`
+}
+
+type CustomerLoginRequest struct {
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Password string `json:"password" validate:"required"`
+}
+
+type GuestSessionRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=50"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required"`
 }
 
 type LoginResponse struct {
@@ -39,3 +85,38 @@ type LoginResponse struct {
 	User    *User  `json:"user,omitempty"`
 	Error   string `json:"error,omitempty"`
 }
+
+type UserResponse struct {
+	Success bool  `json:"success"`
+	User    *User `json:"user"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type UpdateProfileRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=2,max=50"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+type UsersPageResponse struct {
+	Success  bool    `json:"success"`
+	Users    []*User `json:"users"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"page_size"`
+	Total    int     `json:"total"`
+}
@@ -0,0 +1,197 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/utils"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discoveryTTL = time.Hour
+	// Unknown key ids trigger a JWKS refetch at most this often, so forged
+	// tokens cannot hammer the provider.
+	jwksRefreshInterval = time.Minute
+	// Allowed difference between our clock and the provider's.
+	clockSkew = time.Minute
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrUnknownKey     = errors.New("id token signed with an unknown key")
+)
+
+// Discovery holds the parts of the provider metadata document we use.
+type Discovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Client runs the authorization code flow with PKCE against one provider.
+// Discovery metadata and signing keys are cached, so keep one Client per
+// provider for the lifetime of the process.
+type Client struct {
+	provider    *config.OIDCProviderConfig
+	redirectURL string
+	httpClient  *http.Client
+
+	mu            sync.Mutex
+	discovery     *Discovery
+	discoveredAt  time.Time
+	keys          map[string]*jsonWebKey
+	keysFetchedAt time.Time
+}
+
+func NewClient(provider *config.OIDCProviderConfig, redirectURL string) *Client {
+	return &Client{
+		provider:    provider,
+		redirectURL: redirectURL,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *Client) Provider() *config.OIDCProviderConfig {
+	return c.provider
+}
+
+// Discover returns the provider metadata, fetching it from the issuer's
+// well-known URL when the cached copy is missing or stale.
+func (c *Client) Discover(ctx context.Context) (*Discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.discovery != nil && time.Since(c.discoveredAt) < discoveryTTL {
+		return c.discovery, nil
+	}
+
+	var discovery Discovery
+	if err := c.getJSON(ctx, c.provider.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("error fetching discovery document: %v", err)
+	}
+
+	if strings.TrimRight(discovery.Issuer, "/") != c.provider.Issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match configured issuer %q", discovery.Issuer, c.provider.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document is missing required endpoints")
+	}
+
+	c.discovery = &discovery
+	c.discoveredAt = time.Now()
+
+	return c.discovery, nil
+}
+
+// AuthCodeURL builds the URL the browser is sent to for signing in.
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", c.provider.ClientID)
+	params.Set("redirect_uri", c.redirectURL)
+	params.Set("scope", strings.Join(c.provider.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange trades an authorization code for tokens.
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier string) (*TokenResponse, error) {
+	discovery, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.redirectURL)
+	form.Set("client_id", c.provider.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if c.provider.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.provider.ClientID), url.QueryEscape(c.provider.ClientSecret))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling token endpoint: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error reading token response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, utils.Truncate(string(body), 200))
+	}
+
+	var tokens TokenResponse
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("error decoding token response: %v", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("token response has no id_token")
+	}
+
+	return &tokens, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, endpoint string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", endpoint, resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(target)
+}
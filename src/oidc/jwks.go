@@ -0,0 +1,312 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type IDTokenClaims struct {
+	Issuer          string       `json:"iss"`
+	Subject         string       `json:"sub"`
+	Audience        audience     `json:"aud"`
+	AuthorizedParty string       `json:"azp"`
+	ExpiresAt       int64        `json:"exp"`
+	IssuedAt        int64        `json:"iat"`
+	Nonce           string       `json:"nonce"`
+	Email           string       `json:"email"`
+	EmailVerified   flexibleBool `json:"email_verified"`
+	Name            string       `json:"name"`
+}
+
+// audience accepts both forms of the aud claim, a string or an array.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+func (a audience) contains(value string) bool {
+	for _, item := range a {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// flexibleBool accepts true and "true", some providers send email_verified
+// as a string.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	*b = flexibleBool(value == "true")
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+
+	publicKey crypto.PublicKey
+}
+
+type jsonWebKeySet struct {
+	Keys []*jsonWebKey `json:"keys"`
+}
+
+type idTokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// VerifyIDToken checks the signature against the provider's published keys
+// and validates issuer, audience, expiry and nonce. Only RS256 and ES256
+// are accepted, "none" and HMAC algorithms are always rejected.
+func (c *Client) VerifyIDToken(ctx context.Context, rawToken, nonce string) (*IDTokenClaims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidIDToken
+	}
+
+	var header idTokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidIDToken, header.Alg)
+	}
+
+	key, err := c.signingKey(ctx, header.Kid, header.Alg)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	if err := verifySignature(key, header.Alg, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	var claims IDTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+
+	now := time.Now()
+
+	switch {
+	case strings.TrimRight(claims.Issuer, "/") != c.provider.Issuer:
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidIDToken)
+	case !claims.Audience.contains(c.provider.ClientID):
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidIDToken)
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != c.provider.ClientID:
+		return nil, fmt.Errorf("%w: unexpected authorized party", ErrInvalidIDToken)
+	case claims.ExpiresAt == 0 || now.Add(-clockSkew).Unix() >= claims.ExpiresAt:
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidIDToken)
+	case claims.IssuedAt > now.Add(clockSkew).Unix():
+		return nil, fmt.Errorf("%w: token issued in the future", ErrInvalidIDToken)
+	case claims.Nonce != nonce:
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return &claims, nil
+}
+
+// signingKey looks the key up in the cached set and refetches the set
+// once when the id is unknown, which is how providers roll their keys.
+func (c *Client) signingKey(ctx context.Context, kid, alg string) (*jsonWebKey, error) {
+	discovery, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key := c.findKey(kid, alg); key != nil {
+		return key, nil
+	}
+
+	if !c.keysFetchedAt.IsZero() && time.Since(c.keysFetchedAt) < jwksRefreshInterval {
+		return nil, ErrUnknownKey
+	}
+
+	var set jsonWebKeySet
+	if err := c.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("error fetching signing keys: %v", err)
+	}
+
+	keys := map[string]*jsonWebKey{}
+	for i, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if err := key.parse(); err != nil {
+			continue
+		}
+		id := key.Kid
+		if id == "" {
+			id = fmt.Sprintf("#%d", i)
+		}
+		keys[id] = key
+	}
+
+	c.keys = keys
+	c.keysFetchedAt = time.Now()
+
+	if key := c.findKey(kid, alg); key != nil {
+		return key, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// findKey matches on the key id, or on the key type when the token has no
+// id and the provider publishes a single key of that type.
+func (c *Client) findKey(kid, alg string) *jsonWebKey {
+	if kid != "" {
+		if key, ok := c.keys[kid]; ok && key.supports(alg) {
+			return key
+		}
+		return nil
+	}
+
+	var match *jsonWebKey
+	for _, key := range c.keys {
+		if key.supports(alg) {
+			if match != nil {
+				return nil
+			}
+			match = key
+		}
+	}
+	return match
+}
+
+func (k *jsonWebKey) supports(alg string) bool {
+	if k.Alg != "" && k.Alg != alg {
+		return false
+	}
+
+	switch alg {
+	case "RS256":
+		_, ok := k.publicKey.(*rsa.PublicKey)
+		return ok
+	case "ES256":
+		_, ok := k.publicKey.(*ecdsa.PublicKey)
+		return ok
+	}
+	return false
+}
+
+func (k *jsonWebKey) parse() error {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return err
+		}
+
+		k.publicKey = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		return nil
+
+	case "EC":
+		if k.Crv != "P-256" {
+			return fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return err
+		}
+
+		key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return fmt.Errorf("point is not on the curve")
+		}
+
+		k.publicKey = key
+		return nil
+	}
+
+	return fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func verifySignature(key *jsonWebKey, alg string, digest, signature []byte) error {
+	switch alg {
+	case "RS256":
+		return rsa.VerifyPKCS1v15(key.publicKey.(*rsa.PublicKey), crypto.SHA256, digest, signature)
+	case "ES256":
+		if len(signature) != 64 {
+			return fmt.Errorf("malformed signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(key.publicKey.(*ecdsa.PublicKey), digest, r, s) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+func decodeSegment(segment string, target any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,31 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// GenerateCodeVerifier returns a 43 character PKCE verifier (RFC 7636).
+func GenerateCodeVerifier() string {
+	return randomString(32)
+}
+
+// GenerateNonce returns a random value binding an id token to one login.
+func GenerateNonce() string {
+	return randomString(32)
+}
+
+// CodeChallenge derives the S256 challenge sent with the authorization
+// request from the verifier kept on the server.
+func CodeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func randomString(size int) string {
+	bytes := make([]byte, size)
+	rand.Read(bytes)
+
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db}
+}
+
+const apiKeyColumns = `id, restaurant_id, name, key_prefix, key_hash, scopes, created_by, expires_at, last_used_at, revoked_at, created_at`
+
+func scanAPIKey(row interface{ Scan(...any) error }) (*models.APIKey, error) {
+	key := &models.APIKey{}
+
+	err := row.Scan(
+		&key.Id,
+		&key.RestaurantId,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		pq.Array(&key.Scopes),
+		&key.CreatedBy,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+
+	return key, err
+}
+
+func (ar *APIKeyRepository) CreateAPIKey(key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (restaurant_id, name, key_prefix, key_hash, scopes, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+	key.CreatedAt = time.Now()
+
+	err := ar.db.QueryRow(
+		query,
+		key.RestaurantId,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		pq.Array(key.Scopes),
+		key.CreatedBy,
+		key.ExpiresAt,
+		key.CreatedAt,
+	).Scan(&key.Id)
+	if err != nil {
+		log.Printf("ERROR: Failed to create API key: %v", err)
+		return fmt.Errorf("error creating API key: %v", err)
+	}
+
+	return nil
+}
+
+// GetActiveAPIKeyByHash returns nil for unknown, expired or revoked keys.
+func (ar *APIKeyRepository) GetActiveAPIKeyByHash(keyHash string) (*models.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)`
+
+	key, err := scanAPIKey(ar.db.QueryRow(query, keyHash, time.Now()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get API key: %v", err)
+		return nil, fmt.Errorf("error getting API key: %v", err)
+	}
+
+	return key, nil
+}
+
+func (ar *APIKeyRepository) GetAPIKeys(restaurantId uuid.UUID) ([]*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE restaurant_id = $1 ORDER BY created_at DESC`
+
+	rows, err := ar.db.Query(query, restaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to get API keys: %v", err)
+		return nil, fmt.Errorf("error getting API keys: %v", err)
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan API key: %v", err)
+			return nil, fmt.Errorf("error scanning API key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (ar *APIKeyRepository) TouchAPIKey(id uuid.UUID, usedAt time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`
+
+	if _, err := ar.db.Exec(query, usedAt, id); err != nil {
+		log.Printf("ERROR: Failed to update API key usage: %v", err)
+		return fmt.Errorf("error updating API key usage: %v", err)
+	}
+
+	return nil
+}
+
+func (ar *APIKeyRepository) RevokeAPIKey(restaurantId, id uuid.UUID) (bool, error) {
+	query := `UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND restaurant_id = $3 AND revoked_at IS NULL`
+
+	result, err := ar.db.Exec(query, time.Now(), id, restaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to revoke API key: %v", err)
+		return false, fmt.Errorf("error revoking API key: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
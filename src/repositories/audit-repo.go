@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const auditColumns = `id, restaurant_id, actor_id, actor_email, api_key_id, api_key_prefix, action, target_type, target_id, ip_address,
+	user_agent, changes, created_at`
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db}
+}
+
+func (ar *AuditRepository) CreateEntry(entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (restaurant_id, actor_id, actor_email, api_key_id, api_key_prefix, action, target_type, target_id,
+			ip_address, user_agent, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id`
+
+	entry.CreatedAt = time.Now()
+
+	err := ar.db.QueryRow(
+		query,
+		entry.RestaurantId,
+		entry.ActorId,
+		entry.ActorEmail,
+		entry.APIKeyId,
+		entry.APIKeyPrefix,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetId,
+		entry.IPAddress,
+		entry.UserAgent,
+		[]byte(entry.Changes),
+		entry.CreatedAt,
+	).Scan(&entry.Id)
+	if err != nil {
+		log.Printf("ERROR: Failed to create audit entry: %v", err)
+		return fmt.Errorf("error creating audit entry: %v", err)
+	}
+
+	return nil
+}
+
+// ListEntries returns the newest entries first together with the number
+// of entries matching the filter.
+func (ar *AuditRepository) ListEntries(filter models.AuditLogFilter, limit, offset int) ([]*models.AuditEntry, int, error) {
+	conditions := []string{}
+	args := []any{}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	// Entries without a restaurant, such as sign ins, belong to every
+	// restaurant of the actor or of the user they are about. Failed logins
+	// have no actor and name the account as the target when it exists.
+	add(`(restaurant_id = $%[1]d OR (restaurant_id IS NULL AND (
+		actor_id IN (SELECT user_id FROM restaurant_memberships WHERE restaurant_id = $%[1]d)
+		OR (target_type = 'user' AND target_id IN (
+			SELECT user_id::text FROM restaurant_memberships WHERE restaurant_id = $%[1]d)))))`, filter.RestaurantId)
+
+	if filter.ActorId != nil {
+		add("actor_id = $%d", *filter.ActorId)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetId != "" {
+		add("target_id = $%d", filter.TargetId)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+
+	if err := ar.db.QueryRow(`SELECT COUNT(*) FROM audit_log `+where, args...).Scan(&total); err != nil {
+		log.Printf("ERROR: Failed to count audit entries: %v", err)
+		return nil, 0, fmt.Errorf("error counting audit entries: %v", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+auditColumns+`
+		FROM audit_log
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+
+	rows, err := ar.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		log.Printf("ERROR: Failed to list audit entries: %v", err)
+		return nil, 0, fmt.Errorf("error listing audit entries: %v", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanAuditEntries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// GetUserEntries returns every entry the user caused or that is about the
+// user, oldest first. The email, IP address and user agent of other actors
+// are left empty, they are not the user's data.
+func (ar *AuditRepository) GetUserEntries(userId uuid.UUID) ([]*models.AuditEntry, error) {
+	query := `
+		SELECT id, restaurant_id, actor_id,
+			CASE WHEN actor_id = $1 THEN actor_email ELSE '' END,
+			api_key_id, api_key_prefix, action, target_type, target_id,
+			CASE WHEN actor_id = $1 THEN ip_address ELSE '' END,
+			CASE WHEN actor_id = $1 THEN user_agent ELSE '' END,
+			changes, created_at
+		FROM audit_log
+		WHERE actor_id = $1 OR (target_type = $2 AND target_id = $3)
+		ORDER BY created_at`
+
+	rows, err := ar.db.Query(query, userId, models.AuditTargetUser, userId.String())
+	if err != nil {
+		log.Printf("ERROR: Failed to get user audit entries: %v", err)
+		return nil, fmt.Errorf("error getting user audit entries: %v", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+func scanAuditEntries(rows *sql.Rows) ([]*models.AuditEntry, error) {
+	entries := []*models.AuditEntry{}
+	for rows.Next() {
+		entry := &models.AuditEntry{}
+		var changes []byte
+
+		err := rows.Scan(
+			&entry.Id,
+			&entry.RestaurantId,
+			&entry.ActorId,
+			&entry.ActorEmail,
+			&entry.APIKeyId,
+			&entry.APIKeyPrefix,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetId,
+			&entry.IPAddress,
+			&entry.UserAgent,
+			&changes,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan audit entry: %v", err)
+			return nil, fmt.Errorf("error scanning audit entry: %v", err)
+		}
+
+		entry.Changes = changes
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailVerificationRepository struct {
+	db *sql.DB
+}
+
+func NewEmailVerificationRepository(db *sql.DB) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db}
+}
+
+func (er *EmailVerificationRepository) CreateToken(userId uuid.UUID, email, tokenHash string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO email_verification_tokens (user_id, email, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err := er.db.Exec(query, userId, email, tokenHash, expiresAt, time.Now()); err != nil {
+		log.Printf("ERROR: Failed to create email verification token: %v", err)
+		return fmt.Errorf("error creating email verification token: %v", err)
+	}
+
+	return nil
+}
+
+// ConsumeToken marks a valid token as used and returns the user and the
+// address it was issued for. It returns uuid.Nil when the token is unknown,
+// expired or already used.
+func (er *EmailVerificationRepository) ConsumeToken(tokenHash string) (uuid.UUID, string, error) {
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = $1
+		WHERE token_hash = $2 AND used_at IS NULL AND expires_at > $1
+		RETURNING user_id, email`
+
+	var userId uuid.UUID
+	var email string
+
+	err := er.db.QueryRow(query, time.Now(), tokenHash).Scan(&userId, &email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, "", nil
+		}
+
+		log.Printf("ERROR: Failed to consume email verification token: %v", err)
+		return uuid.Nil, "", fmt.Errorf("error consuming email verification token: %v", err)
+	}
+
+	return userId, email, nil
+}
+
+func (er *EmailVerificationRepository) InvalidateUserTokens(userId uuid.UUID) error {
+	query := `UPDATE email_verification_tokens SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL`
+
+	if _, err := er.db.Exec(query, time.Now(), userId); err != nil {
+		log.Printf("ERROR: Failed to invalidate email verification tokens: %v", err)
+		return fmt.Errorf("error invalidating email verification tokens: %v", err)
+	}
+
+	return nil
+}
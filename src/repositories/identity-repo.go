@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IdentityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{db}
+}
+
+const identityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+func scanIdentity(row interface{ Scan(...any) error }) (*models.UserIdentity, error) {
+	identity := &models.UserIdentity{}
+
+	err := row.Scan(
+		&identity.Id,
+		&identity.UserId,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+
+	return identity, err
+}
+
+func (ir *IdentityRepository) GetIdentity(provider, subject string) (*models.UserIdentity, error) {
+	query := `SELECT ` + identityColumns + ` FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	identity, err := scanIdentity(ir.db.QueryRow(query, provider, subject))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get user identity: %v", err)
+		return nil, fmt.Errorf("error getting user identity: %v", err)
+	}
+
+	return identity, nil
+}
+
+func (ir *IdentityRepository) CreateIdentity(identity *models.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	identity.CreatedAt = time.Now()
+
+	err := ir.db.QueryRow(query, identity.UserId, identity.Provider, identity.Subject, identity.Email, identity.CreatedAt).Scan(&identity.Id)
+	if err != nil {
+		log.Printf("ERROR: Failed to create user identity: %v", err)
+		return fmt.Errorf("error creating user identity: %v", err)
+	}
+
+	return nil
+}
+
+func (ir *IdentityRepository) TouchIdentity(id uuid.UUID, email string) error {
+	query := `UPDATE user_identities SET last_login_at = $1, email = $2 WHERE id = $3`
+
+	if _, err := ir.db.Exec(query, time.Now(), email, id); err != nil {
+		log.Printf("ERROR: Failed to update user identity: %v", err)
+		return fmt.Errorf("error updating user identity: %v", err)
+	}
+
+	return nil
+}
+
+func (ir *IdentityRepository) GetUserIdentities(userId uuid.UUID) ([]*models.UserIdentity, error) {
+	query := `SELECT ` + identityColumns + ` FROM user_identities WHERE user_id = $1 ORDER BY created_at`
+
+	rows, err := ir.db.Query(query, userId)
+	if err != nil {
+		log.Printf("ERROR: Failed to get user identities: %v", err)
+		return nil, fmt.Errorf("error getting user identities: %v", err)
+	}
+	defer rows.Close()
+
+	identities := []*models.UserIdentity{}
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan user identity: %v", err)
+			return nil, fmt.Errorf("error scanning user identity: %v", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, rows.Err()
+}
+
+// DeleteIdentity only removes the identity when it belongs to the user.
+func (ir *IdentityRepository) DeleteIdentity(userId, id uuid.UUID) (bool, error) {
+	result, err := ir.db.Exec(`DELETE FROM user_identities WHERE id = $1 AND user_id = $2`, id, userId)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete user identity: %v", err)
+		return false, fmt.Errorf("error deleting user identity: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (ir *IdentityRepository) CreateState(state *models.OIDCLoginState) error {
+	if _, err := ir.db.Exec(`DELETE FROM oidc_login_states WHERE expires_at < $1`, time.Now()); err != nil {
+		log.Printf("ERROR: Failed to delete expired OIDC states: %v", err)
+	}
+
+	query := `
+		INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, return_to, link_user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := ir.db.Exec(query, state.StateHash, state.Provider, state.Nonce, state.CodeVerifier, state.ReturnTo, state.LinkUserId,
+		state.ExpiresAt, time.Now())
+	if err != nil {
+		log.Printf("ERROR: Failed to create OIDC state: %v", err)
+		return fmt.Errorf("error creating OIDC state: %v", err)
+	}
+
+	return nil
+}
+
+// ConsumeState deletes a pending state and returns it, or nil when it is
+// unknown or expired. Each state can be used once.
+func (ir *IdentityRepository) ConsumeState(stateHash string) (*models.OIDCLoginState, error) {
+	query := `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1
+		RETURNING state_hash, provider, nonce, code_verifier, return_to, link_user_id, expires_at`
+
+	state := &models.OIDCLoginState{}
+
+	err := ir.db.QueryRow(query, stateHash).Scan(
+		&state.StateHash,
+		&state.Provider,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ReturnTo,
+		&state.LinkUserId,
+		&state.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to consume OIDC state: %v", err)
+		return nil, fmt.Errorf("error consuming OIDC state: %v", err)
+	}
+
+	if time.Now().After(state.ExpiresAt) {
+		return nil, nil
+	}
+
+	return state, nil
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const invitationColumns = `id, restaurant_id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at`
+
+type InvitationRepository struct {
+	db *sql.DB
+}
+
+func NewInvitationRepository(db *sql.DB) *InvitationRepository {
+	return &InvitationRepository{db}
+}
+
+func scanInvitation(row interface{ Scan(...any) error }) (*models.Invitation, error) {
+	invitation := &models.Invitation{}
+
+	err := row.Scan(&invitation.Id, &invitation.RestaurantId, &invitation.Email, &invitation.Role, &invitation.TokenHash, &invitation.InvitedBy,
+		&invitation.ExpiresAt, &invitation.AcceptedAt, &invitation.RevokedAt, &invitation.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (ir *InvitationRepository) CreateInvitation(invitation *models.Invitation) error {
+	query := `
+		INSERT INTO invitations (restaurant_id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	invitation.CreatedAt = time.Now()
+
+	err := ir.db.QueryRow(query, invitation.RestaurantId, invitation.Email, invitation.Role, invitation.TokenHash, invitation.InvitedBy,
+		invitation.ExpiresAt, invitation.CreatedAt).Scan(&invitation.Id)
+	if err != nil {
+		log.Printf("ERROR: Failed to create invitation: %v", err)
+		return fmt.Errorf("error creating invitation: %v", err)
+	}
+
+	return nil
+}
+
+func (ir *InvitationRepository) GetPendingInvitationByTokenHash(tokenHash string) (*models.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM invitations
+		WHERE token_hash = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > $2`
+
+	invitation, err := scanInvitation(ir.db.QueryRow(query, tokenHash, time.Now()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get invitation by token: %v", err)
+		return nil, fmt.Errorf("error getting invitation by token: %v", err)
+	}
+
+	return invitation, nil
+}
+
+func (ir *InvitationRepository) GetPendingInvitations(restaurantId uuid.UUID) ([]*models.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM invitations
+		WHERE restaurant_id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY created_at DESC`
+
+	rows, err := ir.db.Query(query, restaurantId, time.Now())
+	if err != nil {
+		log.Printf("ERROR: Failed to get pending invitations: %v", err)
+		return nil, fmt.Errorf("error getting pending invitations: %v", err)
+	}
+	defer rows.Close()
+
+	invitations := []*models.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan invitation: %v", err)
+			return nil, fmt.Errorf("error scanning invitation: %v", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+// Accept claims the invitation and creates the user as a member of its
+// restaurant in one transaction. It reports false and changes nothing when
+// the invitation was accepted, revoked or expired in the meantime.
+func (ir *InvitationRepository) Accept(invitation *models.Invitation, user *models.User) (bool, error) {
+	return ir.accept(invitation, user, true)
+}
+
+// Join is Accept for an account that already exists.
+func (ir *InvitationRepository) Join(invitation *models.Invitation, user *models.User) (bool, error) {
+	return ir.accept(invitation, user, false)
+}
+
+func (ir *InvitationRepository) accept(invitation *models.Invitation, user *models.User, create bool) (bool, error) {
+	tx, err := ir.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to accept invitation: %v", err)
+		return false, fmt.Errorf("error accepting invitation: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	query := `
+		UPDATE invitations SET accepted_at = $1
+		WHERE id = $2 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > $1
+		RETURNING accepted_at`
+
+	if err := tx.QueryRow(query, now, invitation.Id).Scan(&invitation.AcceptedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		log.Printf("ERROR: Failed to accept invitation: %v", err)
+		return false, fmt.Errorf("error accepting invitation: %v", err)
+	}
+
+	if create {
+		if err := insertUser(tx, user); err != nil {
+			return false, err
+		}
+	}
+
+	_, err = insertMember(tx, &models.Membership{
+		UserId:       user.Id,
+		RestaurantId: invitation.RestaurantId,
+		Role:         invitation.Role,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to accept invitation: %v", err)
+		return false, fmt.Errorf("error accepting invitation: %v", err)
+	}
+
+	return true, nil
+}
+
+func (ir *InvitationRepository) RevokeInvitation(restaurantId, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE invitations SET revoked_at = $1
+		WHERE id = $2 AND restaurant_id = $3 AND accepted_at IS NULL AND revoked_at IS NULL`
+
+	result, err := ir.db.Exec(query, time.Now(), id, restaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to revoke invitation: %v", err)
+		return false, fmt.Errorf("error revoking invitation: %v", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+// RevokePendingInvitationsByEmail supersedes older invitations when the
+// same address is invited to the same restaurant again.
+func (ir *InvitationRepository) RevokePendingInvitationsByEmail(restaurantId uuid.UUID, email string) error {
+	query := `
+		UPDATE invitations SET revoked_at = $1
+		WHERE restaurant_id = $2 AND email = $3 AND accepted_at IS NULL AND revoked_at IS NULL`
+
+	if _, err := ir.db.Exec(query, time.Now(), restaurantId, email); err != nil {
+		log.Printf("ERROR: Failed to revoke invitations by email: %v", err)
+		return fmt.Errorf("error revoking invitations by email: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+)
+
+type LoginThrottleRepository struct {
+	db *sql.DB
+}
+
+func NewLoginThrottleRepository(db *sql.DB) *LoginThrottleRepository {
+	return &LoginThrottleRepository{db}
+}
+
+func (lr *LoginThrottleRepository) GetThrottle(scope, key string) (*models.LoginThrottle, error) {
+	query := `SELECT scope, key, failed_count, last_failed_at, locked_until FROM login_throttles WHERE scope = $1 AND key = $2`
+
+	throttle := &models.LoginThrottle{}
+
+	err := lr.db.QueryRow(query, scope, key).Scan(&throttle.Scope, &throttle.Key, &throttle.FailedCount,
+		&throttle.LastFailedAt, &throttle.LockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get login throttle: %v", err)
+		return nil, fmt.Errorf("error getting login throttle: %v", err)
+	}
+
+	return throttle, nil
+}
+
+// BeginAttempt counts an attempt against every key in one transaction,
+// before the credentials are checked, so concurrent attempts cannot all get
+// past the same check. An attempt is refused, and nothing is counted, while
+// a key is locked or its backoff has not passed. The backoff doubles from
+// base with every counted attempt, up to limit. Counters whose last attempt
+// is older than windowStart start again from one.
+func (lr *LoginThrottleRepository) BeginAttempt(keys []models.ThrottleKey, windowStart time.Time, base, limit time.Duration) (bool, error) {
+	query := `
+		INSERT INTO login_throttles (scope, key, failed_count, last_failed_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failed_count = CASE WHEN login_throttles.last_failed_at < $4 THEN 1 ELSE login_throttles.failed_count + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at
+		WHERE (login_throttles.locked_until IS NULL OR login_throttles.locked_until <= $3)
+			AND (login_throttles.last_failed_at < $4
+				OR login_throttles.failed_count <= 0
+				OR login_throttles.last_failed_at + LEAST($5 * power(2, LEAST(login_throttles.failed_count - 1, 30)), $6)
+					* interval '1 second' <= $3)
+		RETURNING failed_count`
+
+	tx, err := lr.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to register login attempt: %v", err)
+		return false, fmt.Errorf("error registering login attempt: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	for _, key := range keys {
+		var count int
+
+		err := tx.QueryRow(query, key.Scope, key.Key, now, windowStart, base.Seconds(), limit.Seconds()).Scan(&count)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to register login attempt: %v", err)
+			return false, fmt.Errorf("error registering login attempt: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to register login attempt: %v", err)
+		return false, fmt.Errorf("error registering login attempt: %v", err)
+	}
+
+	return true, nil
+}
+
+// CountWithin counts one more event for the key unless max of them have
+// already been counted since windowStart, and reports whether it did.
+// Counters whose last event is older than windowStart start again from one.
+func (lr *LoginThrottleRepository) CountWithin(scope, key string, windowStart time.Time, max int) (bool, error) {
+	query := `
+		INSERT INTO login_throttles (scope, key, failed_count, last_failed_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failed_count = CASE WHEN login_throttles.last_failed_at < $4 THEN 1 ELSE login_throttles.failed_count + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at
+		WHERE login_throttles.last_failed_at < $4 OR login_throttles.failed_count < $5
+		RETURNING failed_count`
+
+	var count int
+
+	err := lr.db.QueryRow(query, scope, key, time.Now(), windowStart, max).Scan(&count)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		log.Printf("ERROR: Failed to count %s throttle: %v", scope, err)
+		return false, fmt.Errorf("error counting %s throttle: %v", scope, err)
+	}
+
+	return true, nil
+}
+
+// LockIfReached locks the key until the given time once it has counted max
+// attempts, and returns the count it was locked at.
+func (lr *LoginThrottleRepository) LockIfReached(scope, key string, max int, until time.Time) (int, bool, error) {
+	query := `
+		UPDATE login_throttles SET locked_until = $1
+		WHERE scope = $2 AND key = $3 AND failed_count >= $4
+		RETURNING failed_count`
+
+	var count int
+
+	err := lr.db.QueryRow(query, until, scope, key, max).Scan(&count)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+
+		log.Printf("ERROR: Failed to lock login: %v", err)
+		return 0, false, fmt.Errorf("error locking login: %v", err)
+	}
+
+	return count, true, nil
+}
+
+// ReleaseAttempt takes back an attempt that turned out to succeed.
+func (lr *LoginThrottleRepository) ReleaseAttempt(scope, key string) error {
+	query := `UPDATE login_throttles SET failed_count = GREATEST(failed_count - 1, 0) WHERE scope = $1 AND key = $2`
+
+	if _, err := lr.db.Exec(query, scope, key); err != nil {
+		log.Printf("ERROR: Failed to release login attempt: %v", err)
+		return fmt.Errorf("error releasing login attempt: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteStale removes counters that no longer hold anything back, their
+// last attempt is before windowStart and any lock has run out.
+func (lr *LoginThrottleRepository) DeleteStale(windowStart time.Time) (int64, error) {
+	query := `
+		DELETE FROM login_throttles
+		WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until < $2)`
+
+	result, err := lr.db.Exec(query, windowStart, time.Now())
+	if err != nil {
+		log.Printf("ERROR: Failed to delete stale login throttles: %v", err)
+		return 0, fmt.Errorf("error deleting stale login throttles: %v", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (lr *LoginThrottleRepository) Reset(scope, key string) (bool, error) {
+	query := `DELETE FROM login_throttles WHERE scope = $1 AND key = $2`
+
+	result, err := lr.db.Exec(query, scope, key)
+	if err != nil {
+		log.Printf("ERROR: Failed to reset login throttle: %v", err)
+		return false, fmt.Errorf("error resetting login throttle: %v", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
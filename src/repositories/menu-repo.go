@@ -0,0 +1,331 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const menuCategoryColumns = `id, restaurant_id, name, description, position, is_available, created_at, updated_at`
+
+const menuItemColumns = `i.id, i.restaurant_id, i.category_id, i.name, i.description, i.price, i.allergens, i.allergens_declared, i.dietary_tags,
+	i.position, i.is_available, i.created_at, i.updated_at`
+
+type MenuRepository struct {
+	db *sql.DB
+}
+
+func NewMenuRepository(db *sql.DB) *MenuRepository {
+	return &MenuRepository{db}
+}
+
+func scanMenuCategory(row interface{ Scan(...any) error }) (*models.MenuCategory, error) {
+	category := &models.MenuCategory{}
+
+	err := row.Scan(&category.Id, &category.RestaurantId, &category.Name, &category.Description, &category.Position,
+		&category.IsAvailable, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func scanMenuItem(row interface{ Scan(...any) error }) (*models.MenuItem, error) {
+	item := &models.MenuItem{}
+
+	err := row.Scan(&item.Id, &item.RestaurantId, &item.CategoryId, &item.Name, &item.Description, &item.Price,
+		pq.Array(&item.Allergens), &item.AllergensDeclared, pq.Array(&item.DietaryTags), &item.Position, &item.IsAvailable, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// CreateCategory appends the category at the end of the menu unless a
+// position is given.
+func (mr *MenuRepository) CreateCategory(category *models.MenuCategory, position *int) error {
+	query := `
+		INSERT INTO menu_categories (restaurant_id, name, description, position, is_available, created_at, updated_at)
+		VALUES ($1, $2, $3,
+			COALESCE($4::int, (SELECT COALESCE(MAX(position) + 1, 0) FROM menu_categories WHERE restaurant_id = $1)),
+			$5, $6, $6)
+		RETURNING id, position`
+
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = category.CreatedAt
+
+	err := mr.db.QueryRow(query, category.RestaurantId, category.Name, category.Description, position, category.IsAvailable, category.CreatedAt).
+		Scan(&category.Id, &category.Position)
+	if err != nil {
+		log.Printf("ERROR: Failed to create menu category: %v", err)
+		return fmt.Errorf("error creating menu category: %v", err)
+	}
+
+	return nil
+}
+
+func (mr *MenuRepository) GetCategory(restaurantId, id uuid.UUID) (*models.MenuCategory, error) {
+	query := `SELECT ` + menuCategoryColumns + ` FROM menu_categories WHERE id = $1 AND restaurant_id = $2`
+
+	category, err := scanMenuCategory(mr.db.QueryRow(query, id, restaurantId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get menu category: %v", err)
+		return nil, fmt.Errorf("error getting menu category: %v", err)
+	}
+
+	return category, nil
+}
+
+// GetCategories returns the restaurant's categories in menu order, without
+// their items.
+func (mr *MenuRepository) GetCategories(restaurantId uuid.UUID, onlyAvailable bool) ([]*models.MenuCategory, error) {
+	query := `
+		SELECT ` + menuCategoryColumns + `
+		FROM menu_categories
+		WHERE restaurant_id = $1 AND (is_available OR NOT $2)
+		ORDER BY position, name, id`
+
+	rows, err := mr.db.Query(query, restaurantId, onlyAvailable)
+	if err != nil {
+		log.Printf("ERROR: Failed to get menu categories: %v", err)
+		return nil, fmt.Errorf("error getting menu categories: %v", err)
+	}
+	defer rows.Close()
+
+	categories := []*models.MenuCategory{}
+	for rows.Next() {
+		category, err := scanMenuCategory(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan menu category: %v", err)
+			return nil, fmt.Errorf("error scanning menu category: %v", err)
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+func (mr *MenuRepository) UpdateCategory(category *models.MenuCategory) error {
+	query := `
+		UPDATE menu_categories SET name = $1, description = $2, position = $3, is_available = $4, updated_at = $5
+		WHERE id = $6 AND restaurant_id = $7`
+
+	category.UpdatedAt = time.Now()
+
+	_, err := mr.db.Exec(query, category.Name, category.Description, category.Position, category.IsAvailable, category.UpdatedAt,
+		category.Id, category.RestaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to update menu category: %v", err)
+		return fmt.Errorf("error updating menu category: %v", err)
+	}
+
+	return nil
+}
+
+// CategoryHasItems reports whether any item still belongs to the category.
+// Categories can only be deleted once they are empty.
+func (mr *MenuRepository) CategoryHasItems(id uuid.UUID) (bool, error) {
+	var exists bool
+
+	if err := mr.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM menu_items WHERE category_id = $1)`, id).Scan(&exists); err != nil {
+		log.Printf("ERROR: Failed to check menu category items: %v", err)
+		return false, fmt.Errorf("error checking menu category items: %v", err)
+	}
+
+	return exists, nil
+}
+
+func (mr *MenuRepository) DeleteCategory(restaurantId, id uuid.UUID) (bool, error) {
+	query := `DELETE FROM menu_categories WHERE id = $1 AND restaurant_id = $2`
+
+	return deleteTranslated(mr.db, "menu category", query, []any{id, restaurantId}, func(tx *sql.Tx) error {
+		return deleteTranslations(tx, models.TranslationEntityMenuCategory, id)
+	})
+}
+
+// ReorderCategories gives the categories the positions of their ids in the
+// list. It reports false and changes nothing when an id is not one of the
+// restaurant's categories.
+func (mr *MenuRepository) ReorderCategories(restaurantId uuid.UUID, ids []uuid.UUID) (bool, error) {
+	query := `
+		UPDATE menu_categories c SET position = o.position - 1, updated_at = $3
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position)
+		WHERE c.id = o.id AND c.restaurant_id = $1`
+
+	return mr.reorder("menu categories", query, ids, restaurantId)
+}
+
+// ReorderItems works like ReorderCategories for the items of one category.
+func (mr *MenuRepository) ReorderItems(restaurantId, categoryId uuid.UUID, ids []uuid.UUID) (bool, error) {
+	query := `
+		UPDATE menu_items i SET position = o.position - 1, updated_at = $3
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position)
+		WHERE i.id = o.id AND i.restaurant_id = $1 AND i.category_id = $4`
+
+	return mr.reorder("menu items", query, ids, restaurantId, categoryId)
+}
+
+func (mr *MenuRepository) reorder(what, query string, ids []uuid.UUID, restaurantId uuid.UUID, extra ...any) (bool, error) {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	tx, err := mr.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to reorder %s: %v", what, err)
+		return false, fmt.Errorf("error reordering %s: %v", what, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, append([]any{restaurantId, pq.Array(values), time.Now()}, extra...)...)
+	if err != nil {
+		log.Printf("ERROR: Failed to reorder %s: %v", what, err)
+		return false, fmt.Errorf("error reordering %s: %v", what, err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if updated != int64(len(ids)) {
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to reorder %s: %v", what, err)
+		return false, fmt.Errorf("error reordering %s: %v", what, err)
+	}
+
+	return true, nil
+}
+
+// CreateItem appends the item at the end of its category unless a position
+// is given.
+func (mr *MenuRepository) CreateItem(item *models.MenuItem, position *int) error {
+	query := `
+		INSERT INTO menu_items (restaurant_id, category_id, name, description, price, allergens, allergens_declared, dietary_tags,
+			position, is_available, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
+			COALESCE($9::int, (SELECT COALESCE(MAX(position) + 1, 0) FROM menu_items WHERE category_id = $2)),
+			$10, $11, $11)
+		RETURNING id, position`
+
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = item.CreatedAt
+
+	err := mr.db.QueryRow(query, item.RestaurantId, item.CategoryId, item.Name, item.Description, item.Price,
+		pq.Array(item.Allergens), item.AllergensDeclared, pq.Array(item.DietaryTags), position, item.IsAvailable, item.CreatedAt).Scan(&item.Id, &item.Position)
+	if err != nil {
+		log.Printf("ERROR: Failed to create menu item: %v", err)
+		return fmt.Errorf("error creating menu item: %v", err)
+	}
+
+	return nil
+}
+
+func (mr *MenuRepository) GetItem(restaurantId, id uuid.UUID) (*models.MenuItem, error) {
+	query := `SELECT ` + menuItemColumns + ` FROM menu_items i WHERE i.id = $1 AND i.restaurant_id = $2`
+
+	item, err := scanMenuItem(mr.db.QueryRow(query, id, restaurantId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get menu item: %v", err)
+		return nil, fmt.Errorf("error getting menu item: %v", err)
+	}
+
+	return item, nil
+}
+
+// GetItems returns matching items in menu order, grouped by category.
+func (mr *MenuRepository) GetItems(filter models.MenuItemFilter) ([]*models.MenuItem, error) {
+	conditions := []string{}
+	args := []any{}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	add("i.restaurant_id = $%d", filter.RestaurantId)
+
+	if filter.CategoryId != nil {
+		add("i.category_id = $%d", *filter.CategoryId)
+	}
+	if filter.IsAvailable != nil {
+		add("i.is_available = $%d", *filter.IsAvailable)
+	}
+	if len(filter.ExcludeAllergens) > 0 {
+		add("i.allergens_declared AND NOT (i.allergens && $%d::text[])", pq.Array(filter.ExcludeAllergens))
+	}
+	if len(filter.DietaryTags) > 0 {
+		add("i.dietary_tags @> $%d::text[]", pq.Array(filter.DietaryTags))
+	}
+
+	query := `
+		SELECT ` + menuItemColumns + `
+		FROM menu_items i
+		JOIN menu_categories c ON c.id = i.category_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY c.position, c.id, i.position, i.name, i.id`
+
+	rows, err := mr.db.Query(query, args...)
+	if err != nil {
+		log.Printf("ERROR: Failed to get menu items: %v", err)
+		return nil, fmt.Errorf("error getting menu items: %v", err)
+	}
+	defer rows.Close()
+
+	items := []*models.MenuItem{}
+	for rows.Next() {
+		item, err := scanMenuItem(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan menu item: %v", err)
+			return nil, fmt.Errorf("error scanning menu item: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (mr *MenuRepository) UpdateItem(item *models.MenuItem) error {
+	query := `
+		UPDATE menu_items SET category_id = $1, name = $2, description = $3, price = $4, allergens = $5, allergens_declared = $6,
+			dietary_tags = $7, position = $8, is_available = $9, updated_at = $10
+		WHERE id = $11 AND restaurant_id = $12`
+
+	item.UpdatedAt = time.Now()
+
+	_, err := mr.db.Exec(query, item.CategoryId, item.Name, item.Description, item.Price, pq.Array(item.Allergens), item.AllergensDeclared,
+		pq.Array(item.DietaryTags), item.Position, item.IsAvailable, item.UpdatedAt, item.Id, item.RestaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to update menu item: %v", err)
+		return fmt.Errorf("error updating menu item: %v", err)
+	}
+
+	return nil
+}
+
+func (mr *MenuRepository) DeleteItem(restaurantId, id uuid.UUID) (bool, error) {
+	query := `DELETE FROM menu_items WHERE id = $1 AND restaurant_id = $2`
+
+	return deleteTranslated(mr.db, "menu item", query, []any{id, restaurantId}, func(tx *sql.Tx) error {
+		return deleteTranslations(tx, models.TranslationEntityMenuItem, id)
+	})
+}
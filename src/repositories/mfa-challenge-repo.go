@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+type MFAChallengeRepository struct {
+	db *sql.DB
+}
+
+func NewMFAChallengeRepository(db *sql.DB) *MFAChallengeRepository {
+	return &MFAChallengeRepository{db}
+}
+
+// MarkUsed records that the challenge completed a login and reports false
+// when it already had.
+func (mr *MFAChallengeRepository) MarkUsed(id string, expiresAt time.Time) (bool, error) {
+	if _, err := mr.db.Exec(`DELETE FROM used_mfa_challenges WHERE expires_at < $1`, time.Now()); err != nil {
+		log.Printf("ERROR: Failed to delete expired MFA challenges: %v", err)
+	}
+
+	query := `INSERT INTO used_mfa_challenges (id, expires_at) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING`
+
+	result, err := mr.db.Exec(query, id, expiresAt)
+	if err != nil {
+		log.Printf("ERROR: Failed to mark MFA challenge used: %v", err)
+		return false, fmt.Errorf("error marking MFA challenge used: %v", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return inserted > 0, nil
+}
@@ -0,0 +1,379 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const modifierGroupColumns = `g.id, g.restaurant_id, g.name, g.is_required, g.min_selections, g.max_selections, g.created_at, g.updated_at`
+
+const modifierOptionColumns = `id, group_id, name, price_delta, allergens, allergens_declared, position, is_available, created_at,
+	updated_at`
+
+type ModifierRepository struct {
+	db *sql.DB
+}
+
+func NewModifierRepository(db *sql.DB) *ModifierRepository {
+	return &ModifierRepository{db}
+}
+
+func scanModifierGroup(row interface{ Scan(...any) error }) (*models.ModifierGroup, error) {
+	group := &models.ModifierGroup{Options: []*models.ModifierOption{}}
+
+	err := row.Scan(&group.Id, &group.RestaurantId, &group.Name, &group.IsRequired, &group.MinSelections, &group.MaxSelections,
+		&group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func scanModifierOption(row interface{ Scan(...any) error }) (*models.ModifierOption, error) {
+	option := &models.ModifierOption{}
+
+	err := row.Scan(&option.Id, &option.GroupId, &option.Name, &option.PriceDelta, pq.Array(&option.Allergens), &option.AllergensDeclared,
+		&option.Position, &option.IsAvailable, &option.CreatedAt, &option.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return option, nil
+}
+
+// CreateGroup stores the group together with its first options.
+func (mr *ModifierRepository) CreateGroup(group *models.ModifierGroup) error {
+	tx, err := mr.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to create modifier group: %v", err)
+		return fmt.Errorf("error creating modifier group: %v", err)
+	}
+	defer tx.Rollback()
+
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = group.CreatedAt
+
+	err = tx.QueryRow(`
+		INSERT INTO modifier_groups (restaurant_id, name, is_required, min_selections, max_selections, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id`,
+		group.RestaurantId, group.Name, group.IsRequired, group.MinSelections, group.MaxSelections, group.CreatedAt,
+	).Scan(&group.Id)
+	if err != nil {
+		log.Printf("ERROR: Failed to create modifier group: %v", err)
+		return fmt.Errorf("error creating modifier group: %v", err)
+	}
+
+	for _, option := range group.Options {
+		option.GroupId = group.Id
+		if err := insertModifierOption(tx, option, &option.Position); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to create modifier group: %v", err)
+		return fmt.Errorf("error creating modifier group: %v", err)
+	}
+
+	return nil
+}
+
+// GetGroup returns the group with all of its options.
+func (mr *ModifierRepository) GetGroup(restaurantId, id uuid.UUID) (*models.ModifierGroup, error) {
+	query := `SELECT ` + modifierGroupColumns + ` FROM modifier_groups g WHERE g.id = $1 AND g.restaurant_id = $2`
+
+	group, err := scanModifierGroup(mr.db.QueryRow(query, id, restaurantId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get modifier group: %v", err)
+		return nil, fmt.Errorf("error getting modifier group: %v", err)
+	}
+
+	if err := mr.loadOptions([]*models.ModifierGroup{group}); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGroups returns every group of the restaurant with its options.
+func (mr *ModifierRepository) GetGroups(restaurantId uuid.UUID) ([]*models.ModifierGroup, error) {
+	query := `SELECT ` + modifierGroupColumns + ` FROM modifier_groups g WHERE g.restaurant_id = $1 ORDER BY g.name, g.id`
+
+	return mr.queryGroups(query, restaurantId)
+}
+
+// GetItemGroups returns the groups attached to the item in the order they
+// are offered, with their options.
+func (mr *ModifierRepository) GetItemGroups(itemId uuid.UUID) ([]*models.ModifierGroup, error) {
+	query := `
+		SELECT ` + modifierGroupColumns + `
+		FROM menu_item_modifier_groups l
+		JOIN modifier_groups g ON g.id = l.group_id
+		WHERE l.item_id = $1
+		ORDER BY l.position, g.id`
+
+	return mr.queryGroups(query, itemId)
+}
+
+// GetGroupLinks lists which groups are attached to which items of the
+// restaurant, in the order they are offered.
+func (mr *ModifierRepository) GetGroupLinks(restaurantId uuid.UUID) ([]models.ModifierGroupLink, error) {
+	query := `
+		SELECT l.item_id, l.group_id
+		FROM menu_item_modifier_groups l
+		JOIN modifier_groups g ON g.id = l.group_id
+		WHERE g.restaurant_id = $1
+		ORDER BY l.item_id, l.position, l.group_id`
+
+	rows, err := mr.db.Query(query, restaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to get modifier group links: %v", err)
+		return nil, fmt.Errorf("error getting modifier group links: %v", err)
+	}
+	defer rows.Close()
+
+	links := []models.ModifierGroupLink{}
+	for rows.Next() {
+		var link models.ModifierGroupLink
+		if err := rows.Scan(&link.ItemId, &link.GroupId); err != nil {
+			log.Printf("ERROR: Failed to scan modifier group link: %v", err)
+			return nil, fmt.Errorf("error scanning modifier group link: %v", err)
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+func (mr *ModifierRepository) queryGroups(query string, args ...any) ([]*models.ModifierGroup, error) {
+	rows, err := mr.db.Query(query, args...)
+	if err != nil {
+		log.Printf("ERROR: Failed to get modifier groups: %v", err)
+		return nil, fmt.Errorf("error getting modifier groups: %v", err)
+	}
+	defer rows.Close()
+
+	groups := []*models.ModifierGroup{}
+	for rows.Next() {
+		group, err := scanModifierGroup(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan modifier group: %v", err)
+			return nil, fmt.Errorf("error scanning modifier group: %v", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := mr.loadOptions(groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (mr *ModifierRepository) loadOptions(groups []*models.ModifierGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	byId := make(map[uuid.UUID]*models.ModifierGroup, len(groups))
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		byId[group.Id] = group
+		ids[i] = group.Id.String()
+	}
+
+	query := `
+		SELECT ` + modifierOptionColumns + `
+		FROM modifier_options
+		WHERE group_id = ANY($1::uuid[])
+		ORDER BY position, name, id`
+
+	rows, err := mr.db.Query(query, pq.Array(ids))
+	if err != nil {
+		log.Printf("ERROR: Failed to get modifier options: %v", err)
+		return fmt.Errorf("error getting modifier options: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		option, err := scanModifierOption(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan modifier option: %v", err)
+			return fmt.Errorf("error scanning modifier option: %v", err)
+		}
+		byId[option.GroupId].Options = append(byId[option.GroupId].Options, option)
+	}
+
+	return rows.Err()
+}
+
+func (mr *ModifierRepository) UpdateGroup(group *models.ModifierGroup) error {
+	query := `
+		UPDATE modifier_groups SET name = $1, is_required = $2, min_selections = $3, max_selections = $4, updated_at = $5
+		WHERE id = $6 AND restaurant_id = $7`
+
+	group.UpdatedAt = time.Now()
+
+	_, err := mr.db.Exec(query, group.Name, group.IsRequired, group.MinSelections, group.MaxSelections, group.UpdatedAt,
+		group.Id, group.RestaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to update modifier group: %v", err)
+		return fmt.Errorf("error updating modifier group: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteGroup also detaches the group from every item.
+func (mr *ModifierRepository) DeleteGroup(restaurantId, id uuid.UUID) (bool, error) {
+	query := `DELETE FROM modifier_groups WHERE id = $1 AND restaurant_id = $2`
+
+	return deleteTranslated(mr.db, "modifier group", query, []any{id, restaurantId}, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			DELETE FROM translations
+			WHERE entity_type = $1 AND entity_id IN (SELECT id FROM modifier_options WHERE group_id = $2)`,
+			models.TranslationEntityModifierOption, id)
+		if err != nil {
+			log.Printf("ERROR: Failed to delete translations: %v", err)
+			return fmt.Errorf("error deleting translations: %v", err)
+		}
+
+		return deleteTranslations(tx, models.TranslationEntityModifierGroup, id)
+	})
+}
+
+// CreateOption appends the option at the end of its group unless a
+// position is given.
+func (mr *ModifierRepository) CreateOption(option *models.ModifierOption, position *int) error {
+	return insertModifierOption(mr.db, option, position)
+}
+
+func insertModifierOption(db interface {
+	QueryRow(string, ...any) *sql.Row
+}, option *models.ModifierOption, position *int) error {
+	query := `
+		INSERT INTO modifier_options (group_id, name, price_delta, allergens, allergens_declared, position, is_available, created_at,
+			updated_at)
+		VALUES ($1, $2, $3, $4, $5,
+			COALESCE($6::int, (SELECT COALESCE(MAX(position) + 1, 0) FROM modifier_options WHERE group_id = $1)),
+			$7, $8, $8)
+		RETURNING id, position`
+
+	option.CreatedAt = time.Now()
+	option.UpdatedAt = option.CreatedAt
+
+	err := db.QueryRow(query, option.GroupId, option.Name, option.PriceDelta, pq.Array(option.Allergens), option.AllergensDeclared,
+		position, option.IsAvailable, option.CreatedAt).Scan(&option.Id, &option.Position)
+	if err != nil {
+		log.Printf("ERROR: Failed to create modifier option: %v", err)
+		return fmt.Errorf("error creating modifier option: %v", err)
+	}
+
+	return nil
+}
+
+func (mr *ModifierRepository) GetOption(groupId, id uuid.UUID) (*models.ModifierOption, error) {
+	query := `SELECT ` + modifierOptionColumns + ` FROM modifier_options WHERE id = $1 AND group_id = $2`
+
+	option, err := scanModifierOption(mr.db.QueryRow(query, id, groupId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get modifier option: %v", err)
+		return nil, fmt.Errorf("error getting modifier option: %v", err)
+	}
+
+	return option, nil
+}
+
+func (mr *ModifierRepository) UpdateOption(option *models.ModifierOption) error {
+	query := `
+		UPDATE modifier_options SET name = $1, price_delta = $2, allergens = $3, allergens_declared = $4, position = $5,
+			is_available = $6, updated_at = $7
+		WHERE id = $8 AND group_id = $9`
+
+	option.UpdatedAt = time.Now()
+
+	_, err := mr.db.Exec(query, option.Name, option.PriceDelta, pq.Array(option.Allergens), option.AllergensDeclared, option.Position,
+		option.IsAvailable, option.UpdatedAt, option.Id, option.GroupId)
+	if err != nil {
+		log.Printf("ERROR: Failed to update modifier option: %v", err)
+		return fmt.Errorf("error updating modifier option: %v", err)
+	}
+
+	return nil
+}
+
+func (mr *ModifierRepository) DeleteOption(groupId, id uuid.UUID) (bool, error) {
+	query := `DELETE FROM modifier_options WHERE id = $1 AND group_id = $2`
+
+	return deleteTranslated(mr.db, "modifier option", query, []any{id, groupId}, func(tx *sql.Tx) error {
+		return deleteTranslations(tx, models.TranslationEntityModifierOption, id)
+	})
+}
+
+// SetItemGroups replaces the groups attached to the item, offered in the
+// order given. It reports false and changes nothing when an id is not one
+// of the restaurant's groups or appears twice.
+func (mr *ModifierRepository) SetItemGroups(restaurantId, itemId uuid.UUID, groupIds []uuid.UUID) (bool, error) {
+	ids := make([]string, len(groupIds))
+	for i, id := range groupIds {
+		ids[i] = id.String()
+	}
+
+	tx, err := mr.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to set item modifier groups: %v", err)
+		return false, fmt.Errorf("error setting item modifier groups: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM menu_item_modifier_groups WHERE item_id = $1`, itemId); err != nil {
+		log.Printf("ERROR: Failed to set item modifier groups: %v", err)
+		return false, fmt.Errorf("error setting item modifier groups: %v", err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO menu_item_modifier_groups (item_id, group_id, position)
+		SELECT $1, g.id, o.position - 1
+		FROM (SELECT DISTINCT ON (id) id, position FROM unnest($3::uuid[]) WITH ORDINALITY AS u(id, position)) o
+		JOIN modifier_groups g ON g.id = o.id
+		WHERE g.restaurant_id = $2`,
+		itemId, restaurantId, pq.Array(ids))
+	if err != nil {
+		log.Printf("ERROR: Failed to set item modifier groups: %v", err)
+		return false, fmt.Errorf("error setting item modifier groups: %v", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if inserted != int64(len(groupIds)) {
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to set item modifier groups: %v", err)
+		return false, fmt.Errorf("error setting item modifier groups: %v", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db}
+}
+
+func (pr *PasswordResetRepository) CreateToken(userId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)`
+
+	if _, err := pr.db.Exec(query, userId, tokenHash, expiresAt, time.Now()); err != nil {
+		log.Printf("ERROR: Failed to create password reset token: %v", err)
+		return fmt.Errorf("error creating password reset token: %v", err)
+	}
+
+	return nil
+}
+
+// GetTokenUserId returns the owner of a valid token without using it up,
+// or uuid.Nil when the token is unknown, expired or already used.
+func (pr *PasswordResetRepository) GetTokenUserId(tokenHash string) (uuid.UUID, error) {
+	query := `
+		SELECT user_id FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2`
+
+	var userId uuid.UUID
+
+	err := pr.db.QueryRow(query, tokenHash, time.Now()).Scan(&userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get password reset token: %v", err)
+		return uuid.Nil, fmt.Errorf("error getting password reset token: %v", err)
+	}
+
+	return userId, nil
+}
+
+// ResetPassword uses up a valid token and sets the owner's password in one
+// transaction, together with invalidating the owner's other tokens. It
+// returns the owner, or uuid.Nil and changes nothing when the token is
+// unknown, expired or already used.
+func (pr *PasswordResetRepository) ResetPassword(tokenHash, password string) (uuid.UUID, error) {
+	tx, err := pr.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to reset password: %v", err)
+		return uuid.Nil, fmt.Errorf("error resetting password: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	var userId uuid.UUID
+	err = tx.QueryRow(`
+		UPDATE password_reset_tokens
+		SET used_at = $1
+		WHERE token_hash = $2 AND used_at IS NULL AND expires_at > $1
+		RETURNING user_id`,
+		now, tokenHash).Scan(&userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, nil
+		}
+
+		log.Printf("ERROR: Failed to consume password reset token: %v", err)
+		return uuid.Nil, fmt.Errorf("error consuming password reset token: %v", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE users SET password = $1, updated_at = $2 WHERE id = $3`, password, now, userId); err != nil {
+		log.Printf("ERROR: Failed to update user password: %v", err)
+		return uuid.Nil, fmt.Errorf("error updating user password: %v", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE password_reset_tokens SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL`, now, userId); err != nil {
+		log.Printf("ERROR: Failed to invalidate password reset tokens: %v", err)
+		return uuid.Nil, fmt.Errorf("error invalidating password reset tokens: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to reset password: %v", err)
+		return uuid.Nil, fmt.Errorf("error resetting password: %v", err)
+	}
+
+	return userId, nil
+}
+
+func (pr *PasswordResetRepository) InvalidateUserTokens(userId uuid.UUID) error {
+	query := `UPDATE password_reset_tokens SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL`
+
+	if _, err := pr.db.Exec(query, time.Now(), userId); err != nil {
+		log.Printf("ERROR: Failed to invalidate password reset tokens: %v", err)
+		return fmt.Errorf("error invalidating password reset tokens: %v", err)
+	}
+
+	return nil
+}
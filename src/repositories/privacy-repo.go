@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const dataRequestColumns = `id, user_id, type, status, reason, note, processed_by, processed_at, created_at`
+
+// dataRequestQueueCondition limits data_requests to the queue of the
+// restaurant passed as $1. Requests of customer accounts are only in it
+// when $2 is true.
+const dataRequestQueueCondition = `user_id IN (
+	SELECT id FROM users WHERE account_type <> 'staff' AND $2::boolean
+	UNION
+	SELECT user_id FROM restaurant_memberships WHERE restaurant_id = $1)`
+
+// AnonymizedName replaces the name of erased accounts.
+const AnonymizedName = "Deleted user"
+
+type PrivacyRepository struct {
+	db *sql.DB
+}
+
+func NewPrivacyRepository(db *sql.DB) *PrivacyRepository {
+	return &PrivacyRepository{db}
+}
+
+func scanDataRequest(row interface{ Scan(...any) error }) (*models.DataRequest, error) {
+	request := &models.DataRequest{}
+
+	err := row.Scan(&request.Id, &request.UserId, &request.Type, &request.Status, &request.Reason, &request.Note,
+		&request.ProcessedBy, &request.ProcessedAt, &request.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// CreateRequest reports false when the user already has a pending request
+// of the same type.
+func (pr *PrivacyRepository) CreateRequest(request *models.DataRequest) (bool, error) {
+	query := `
+		INSERT INTO data_requests (user_id, type, status, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, type) WHERE status = 'pending' DO NOTHING
+		RETURNING id`
+
+	request.Status = models.DataRequestStatusPending
+	request.CreatedAt = time.Now()
+
+	err := pr.db.QueryRow(query, request.UserId, request.Type, request.Status, request.Reason, request.CreatedAt).Scan(&request.Id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to create data request: %v", err)
+		return false, fmt.Errorf("error creating data request: %v", err)
+	}
+
+	return true, nil
+}
+
+// GetRestaurantRequest returns the request if it is in the restaurant's
+// queue, see ListRequests.
+func (pr *PrivacyRepository) GetRestaurantRequest(restaurantId uuid.UUID, withCustomers bool, id uuid.UUID) (*models.DataRequest, error) {
+	query := `SELECT ` + dataRequestColumns + ` FROM data_requests WHERE id = $3 AND ` + dataRequestQueueCondition
+
+	request, err := scanDataRequest(pr.db.QueryRow(query, restaurantId, withCustomers, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get data request: %v", err)
+		return nil, fmt.Errorf("error getting data request: %v", err)
+	}
+
+	return request, nil
+}
+
+func (pr *PrivacyRepository) GetUserRequests(userId uuid.UUID) ([]*models.DataRequest, error) {
+	query := `SELECT ` + dataRequestColumns + ` FROM data_requests WHERE user_id = $1 ORDER BY created_at DESC`
+
+	return pr.queryRequests(query, userId)
+}
+
+// ListRequests returns one page of the restaurant's queue with the given
+// status, oldest first so it is worked through in order. An empty status
+// lists all. The queue holds requests of the restaurant's staff and, with
+// withCustomers, of customer accounts, which do not belong to any
+// restaurant.
+func (pr *PrivacyRepository) ListRequests(restaurantId uuid.UUID, withCustomers bool, status string, limit, offset int) ([]*models.DataRequest, int, error) {
+	var total int
+
+	countQuery := `SELECT COUNT(*) FROM data_requests WHERE ($3 = '' OR status = $3) AND ` + dataRequestQueueCondition
+	if err := pr.db.QueryRow(countQuery, restaurantId, withCustomers, status).Scan(&total); err != nil {
+		log.Printf("ERROR: Failed to count data requests: %v", err)
+		return nil, 0, fmt.Errorf("error counting data requests: %v", err)
+	}
+
+	query := `
+		SELECT ` + dataRequestColumns + `
+		FROM data_requests
+		WHERE ($3 = '' OR status = $3) AND ` + dataRequestQueueCondition + `
+		ORDER BY created_at, id
+		LIMIT $4 OFFSET $5`
+
+	requests, err := pr.queryRequests(query, restaurantId, withCustomers, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return requests, total, nil
+}
+
+func (pr *PrivacyRepository) queryRequests(query string, args ...any) ([]*models.DataRequest, error) {
+	rows, err := pr.db.Query(query, args...)
+	if err != nil {
+		log.Printf("ERROR: Failed to get data requests: %v", err)
+		return nil, fmt.Errorf("error getting data requests: %v", err)
+	}
+	defer rows.Close()
+
+	requests := []*models.DataRequest{}
+	for rows.Next() {
+		request, err := scanDataRequest(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan data request: %v", err)
+			return nil, fmt.Errorf("error scanning data request: %v", err)
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
+
+// RejectRequest reports false when the request is no longer pending.
+func (pr *PrivacyRepository) RejectRequest(id, processedBy uuid.UUID, note string) (bool, error) {
+	query := `
+		UPDATE data_requests SET status = $1, note = $2, processed_by = $3, processed_at = $4
+		WHERE id = $5 AND status = $6`
+
+	result, err := pr.db.Exec(query, models.DataRequestStatusRejected, note, processedBy, time.Now(), id, models.DataRequestStatusPending)
+	if err != nil {
+		log.Printf("ERROR: Failed to reject data request: %v", err)
+		return false, fmt.Errorf("error rejecting data request: %v", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+// CompleteErasure anonymizes the user and closes the request in one
+// transaction, and reports false when the request is no longer pending.
+//
+// The users row itself stays, only its personal fields are overwritten,
+// so anything that references it, such as orders and payments, stays
+// intact. Credentials, sessions, linked identities and memberships are
+// deleted, and the audit log keeps its entries without the personal
+// details.
+func (pr *PrivacyRepository) CompleteErasure(request *models.DataRequest, processedBy uuid.UUID, note string) (bool, error) {
+	tx, err := pr.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to start erasure: %v", err)
+		return false, fmt.Errorf("error starting erasure: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	result, err := tx.Exec(`
+		UPDATE data_requests SET status = $1, note = $2, processed_by = $3, processed_at = $4
+		WHERE id = $5 AND status = $6`,
+		models.DataRequestStatusCompleted, note, processedBy, now, request.Id, models.DataRequestStatusPending)
+	if err != nil {
+		log.Printf("ERROR: Failed to complete data request: %v", err)
+		return false, fmt.Errorf("error completing data request: %v", err)
+	}
+
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		return false, err
+	}
+
+	var email, phone string
+
+	err = tx.QueryRow(`SELECT COALESCE(email, ''), COALESCE(phone, '') FROM users WHERE id = $1 FOR UPDATE`, request.UserId).Scan(&email, &phone)
+	if err != nil {
+		log.Printf("ERROR: Failed to load user for erasure: %v", err)
+		return false, fmt.Errorf("error loading user for erasure: %v", err)
+	}
+
+	// The placeholder address keeps the email column unique and satisfies
+	// the sign-in check of staff and customer accounts.
+	placeholder := "erased-" + request.UserId.String() + "@erased.invalid"
+
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE users SET name = $1, email = $2, phone = NULL, password = '', totp_secret = '', totp_enabled_at = NULL,
+			totp_last_step = 0, email_verified_at = NULL, is_active = FALSE, deleted_at = COALESCE(deleted_at, $3), updated_at = $3
+			WHERE id = $4`, []any{AnonymizedName, placeholder, now, request.UserId}},
+		{`DELETE FROM sessions WHERE user_id = $1`, []any{request.UserId}},
+		{`DELETE FROM refresh_tokens WHERE user_id = $1`, []any{request.UserId}},
+		{`DELETE FROM password_reset_tokens WHERE user_id = $1`, []any{request.UserId}},
+		{`DELETE FROM email_verification_tokens WHERE user_id = $1`, []any{request.UserId}},
+		{`DELETE FROM user_recovery_codes WHERE user_id = $1`, []any{request.UserId}},
+		{`DELETE FROM user_identities WHERE user_id = $1`, []any{request.UserId}},
+		{`DELETE FROM restaurant_memberships WHERE user_id = $1`, []any{request.UserId}},
+		{`DELETE FROM login_throttles WHERE scope = $1 AND key IN ($2, $3)`, []any{models.ThrottleScopeEmail, email, phone}},
+		{`UPDATE invitations SET email = $1 WHERE email = $2`, []any{placeholder, email}},
+		{`UPDATE audit_log SET actor_email = '', ip_address = '', user_agent = '' WHERE actor_id = $1`, []any{request.UserId}},
+		// Emails and phones are stored as plain values or as AuditDiff
+		// old and new pairs.
+		{`UPDATE audit_log SET changes = changes - 'email' - 'phone' - 'name', ip_address = '', user_agent = ''
+			WHERE (target_type = $1 AND target_id = $2)
+				OR ($3 <> '' AND $3 IN (changes->>'email', changes->'email'->>'old', changes->'email'->>'new'))
+				OR ($4 <> '' AND $4 IN (changes->>'phone', changes->'phone'->>'old', changes->'phone'->>'new'))`,
+			[]any{models.AuditTargetUser, request.UserId.String(), email, phone}},
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			log.Printf("ERROR: Failed to erase user data: %v", err)
+			return false, fmt.Errorf("error erasing user data: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to commit erasure: %v", err)
+		return false, fmt.Errorf("error committing erasure: %v", err)
+	}
+
+	request.Status = models.DataRequestStatusCompleted
+	request.Note = note
+	request.ProcessedBy = &processedBy
+	request.ProcessedAt = &now
+
+	return true, nil
+}
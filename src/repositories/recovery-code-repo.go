@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RecoveryCodeRepository struct {
+	db *sql.DB
+}
+
+func NewRecoveryCodeRepository(db *sql.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db}
+}
+
+// ReplaceCodes drops every existing recovery code of the user and stores
+// the new set.
+func (rr *RecoveryCodeRepository) ReplaceCodes(userId uuid.UUID, codeHashes []string) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userId); err != nil {
+		tx.Rollback()
+		log.Printf("ERROR: Failed to delete recovery codes: %v", err)
+		return fmt.Errorf("error deleting recovery codes: %v", err)
+	}
+
+	now := time.Now()
+	for _, codeHash := range codeHashes {
+		query := `INSERT INTO user_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)`
+
+		if _, err := tx.Exec(query, userId, codeHash, now); err != nil {
+			tx.Rollback()
+			log.Printf("ERROR: Failed to create recovery code: %v", err)
+			return fmt.Errorf("error creating recovery code: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (rr *RecoveryCodeRepository) UseCode(userId uuid.UUID, codeHash string) (bool, error) {
+	query := `UPDATE user_recovery_codes SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL`
+
+	result, err := rr.db.Exec(query, time.Now(), userId, codeHash)
+	if err != nil {
+		log.Printf("ERROR: Failed to use recovery code: %v", err)
+		return false, fmt.Errorf("error using recovery code: %v", err)
+	}
+
+	used, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return used > 0, nil
+}
+
+func (rr *RecoveryCodeRepository) DeleteCodes(userId uuid.UUID) error {
+	if _, err := rr.db.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userId); err != nil {
+		log.Printf("ERROR: Failed to delete recovery codes: %v", err)
+		return fmt.Errorf("error deleting recovery codes: %v", err)
+	}
+
+	return nil
+}
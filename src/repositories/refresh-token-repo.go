@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, user_agent, ip_address, created_at, expires_at, used_at, revoked_at`
+
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db}
+}
+
+func scanRefreshToken(row interface{ Scan(...any) error }) (*models.RefreshToken, error) {
+	token := &models.RefreshToken{}
+
+	err := row.Scan(&token.Id, &token.UserId, &token.FamilyId, &token.TokenHash, &token.UserAgent, &token.IPAddress,
+		&token.CreatedAt, &token.ExpiresAt, &token.UsedAt, &token.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (rr *RefreshTokenRepository) CreateToken(token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, user_agent, ip_address, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	token.CreatedAt = time.Now()
+
+	err := rr.db.QueryRow(query, token.UserId, token.FamilyId, token.TokenHash, token.UserAgent, token.IPAddress,
+		token.CreatedAt, token.ExpiresAt).Scan(&token.Id)
+	if err != nil {
+		log.Printf("ERROR: Failed to create refresh token: %v", err)
+		return fmt.Errorf("error creating refresh token: %v", err)
+	}
+
+	return nil
+}
+
+func (rr *RefreshTokenRepository) GetTokenByHash(tokenHash string) (*models.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1`
+
+	token, err := scanRefreshToken(rr.db.QueryRow(query, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get refresh token: %v", err)
+		return nil, fmt.Errorf("error getting refresh token: %v", err)
+	}
+
+	return token, nil
+}
+
+// UseToken atomically marks a live token as used. It returns nil when the
+// token is unknown, expired, revoked or was already used.
+func (rr *RefreshTokenRepository) UseToken(tokenHash string) (*models.RefreshToken, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = $1
+		WHERE token_hash = $2 AND used_at IS NULL AND revoked_at IS NULL AND expires_at > $1
+		RETURNING ` + refreshTokenColumns
+
+	token, err := scanRefreshToken(rr.db.QueryRow(query, time.Now(), tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to use refresh token: %v", err)
+		return nil, fmt.Errorf("error using refresh token: %v", err)
+	}
+
+	return token, nil
+}
+
+func (rr *RefreshTokenRepository) RevokeFamily(familyId uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+
+	if _, err := rr.db.Exec(query, time.Now(), familyId); err != nil {
+		log.Printf("ERROR: Failed to revoke refresh token family: %v", err)
+		return fmt.Errorf("error revoking refresh token family: %v", err)
+	}
+
+	return nil
+}
+
+func (rr *RefreshTokenRepository) RevokeUserTokens(userId uuid.UUID) (int64, error) {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL AND expires_at > $1`
+
+	result, err := rr.db.Exec(query, time.Now(), userId)
+	if err != nil {
+		log.Printf("ERROR: Failed to revoke user refresh tokens: %v", err)
+		return 0, fmt.Errorf("error revoking user refresh tokens: %v", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (rr *RefreshTokenRepository) DeleteExpiredTokens() (int64, error) {
+	query := `DELETE FROM refresh_tokens WHERE expires_at <= $1`
+
+	result, err := rr.db.Exec(query, time.Now())
+	if err != nil {
+		log.Printf("ERROR: Failed to delete expired refresh tokens: %v", err)
+		return 0, fmt.Errorf("error deleting expired refresh tokens: %v", err)
+	}
+
+	return result.RowsAffected()
+}
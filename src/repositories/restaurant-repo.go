@@ -0,0 +1,232 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const restaurantColumns = `id, organization_id, name, timezone, created_at`
+
+type RestaurantRepository struct {
+	db *sql.DB
+}
+
+func NewRestaurantRepository(db *sql.DB) *RestaurantRepository {
+	return &RestaurantRepository{db}
+}
+
+func scanRestaurant(row interface{ Scan(...any) error }) (*models.Restaurant, error) {
+	restaurant := &models.Restaurant{}
+
+	err := row.Scan(&restaurant.Id, &restaurant.OrganizationId, &restaurant.Name, &restaurant.Timezone, &restaurant.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return restaurant, nil
+}
+
+func scanRestaurantMembership(row interface{ Scan(...any) error }) (*models.RestaurantMembership, error) {
+	restaurant := &models.Restaurant{}
+	membership := &models.RestaurantMembership{Restaurant: restaurant}
+
+	err := row.Scan(&restaurant.Id, &restaurant.OrganizationId, &restaurant.Name, &restaurant.Timezone, &restaurant.CreatedAt, &membership.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+func (rr *RestaurantRepository) CreateOrganization(organization *models.Organization) error {
+	query := `INSERT INTO organizations (name, created_at) VALUES ($1, $2) RETURNING id`
+
+	organization.CreatedAt = time.Now()
+
+	if err := rr.db.QueryRow(query, organization.Name, organization.CreatedAt).Scan(&organization.Id); err != nil {
+		log.Printf("ERROR: Failed to create organization: %v", err)
+		return fmt.Errorf("error creating organization: %v", err)
+	}
+
+	return nil
+}
+
+func (rr *RestaurantRepository) CreateRestaurant(restaurant *models.Restaurant) error {
+	query := `
+		INSERT INTO restaurants (organization_id, name, timezone, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	restaurant.CreatedAt = time.Now()
+
+	err := rr.db.QueryRow(query, restaurant.OrganizationId, restaurant.Name, restaurant.Timezone, restaurant.CreatedAt).Scan(&restaurant.Id)
+	if err != nil {
+		log.Printf("ERROR: Failed to create restaurant: %v", err)
+		return fmt.Errorf("error creating restaurant: %v", err)
+	}
+
+	return nil
+}
+
+func (rr *RestaurantRepository) GetRestaurant(id uuid.UUID) (*models.Restaurant, error) {
+	query := `SELECT ` + restaurantColumns + ` FROM restaurants WHERE id = $1`
+
+	restaurant, err := scanRestaurant(rr.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get restaurant: %v", err)
+		return nil, fmt.Errorf("error getting restaurant: %v", err)
+	}
+
+	return restaurant, nil
+}
+
+// GetUserRestaurant returns the restaurant with the user's role there, or
+// nil when the user is not a member.
+func (rr *RestaurantRepository) GetUserRestaurant(userId, restaurantId uuid.UUID) (*models.RestaurantMembership, error) {
+	query := `
+		SELECT r.id, r.organization_id, r.name, r.timezone, r.created_at, m.role
+		FROM restaurant_memberships m
+		JOIN restaurants r ON r.id = m.restaurant_id
+		WHERE m.user_id = $1 AND m.restaurant_id = $2`
+
+	membership, err := scanRestaurantMembership(rr.db.QueryRow(query, userId, restaurantId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get user restaurant: %v", err)
+		return nil, fmt.Errorf("error getting user restaurant: %v", err)
+	}
+
+	return membership, nil
+}
+
+// GetUserRestaurants lists every restaurant the user belongs to together
+// with their role there.
+func (rr *RestaurantRepository) GetUserRestaurants(userId uuid.UUID) ([]*models.RestaurantMembership, error) {
+	query := `
+		SELECT r.id, r.organization_id, r.name, r.timezone, r.created_at, m.role
+		FROM restaurant_memberships m
+		JOIN restaurants r ON r.id = m.restaurant_id
+		WHERE m.user_id = $1
+		ORDER BY r.name, r.id`
+
+	rows, err := rr.db.Query(query, userId)
+	if err != nil {
+		log.Printf("ERROR: Failed to get user restaurants: %v", err)
+		return nil, fmt.Errorf("error getting user restaurants: %v", err)
+	}
+	defer rows.Close()
+
+	memberships := []*models.RestaurantMembership{}
+	for rows.Next() {
+		membership, err := scanRestaurantMembership(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan user restaurant: %v", err)
+			return nil, fmt.Errorf("error scanning user restaurant: %v", err)
+		}
+		memberships = append(memberships, membership)
+	}
+
+	return memberships, rows.Err()
+}
+
+// AddMember reports false when the user already belongs to the restaurant,
+// the existing role is left as it is.
+func (rr *RestaurantRepository) AddMember(membership *models.Membership) (bool, error) {
+	return insertMember(rr.db, membership)
+}
+
+func insertMember(db interface {
+	Exec(string, ...any) (sql.Result, error)
+}, membership *models.Membership) (bool, error) {
+	query := `
+		INSERT INTO restaurant_memberships (user_id, restaurant_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, restaurant_id) DO NOTHING`
+
+	membership.CreatedAt = time.Now()
+
+	result, err := db.Exec(query, membership.UserId, membership.RestaurantId, membership.Role, membership.CreatedAt)
+	if err != nil {
+		log.Printf("ERROR: Failed to add restaurant member: %v", err)
+		return false, fmt.Errorf("error adding restaurant member: %v", err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return added > 0, nil
+}
+
+func (rr *RestaurantRepository) RemoveMember(restaurantId, userId uuid.UUID) (bool, error) {
+	query := `DELETE FROM restaurant_memberships WHERE restaurant_id = $1 AND user_id = $2`
+
+	result, err := rr.db.Exec(query, restaurantId, userId)
+	if err != nil {
+		log.Printf("ERROR: Failed to remove restaurant member: %v", err)
+		return false, fmt.Errorf("error removing restaurant member: %v", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return removed > 0, nil
+}
+
+// HasMembershipIn reports whether the user works for a restaurant of the
+// organization.
+func (rr *RestaurantRepository) HasMembershipIn(userId, organizationId uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1
+			FROM restaurant_memberships m
+			JOIN restaurants r ON r.id = m.restaurant_id
+			WHERE m.user_id = $1 AND r.organization_id = $2
+		)`
+
+	var exists bool
+
+	if err := rr.db.QueryRow(query, userId, organizationId).Scan(&exists); err != nil {
+		log.Printf("ERROR: Failed to check user memberships: %v", err)
+		return false, fmt.Errorf("error checking user memberships: %v", err)
+	}
+
+	return exists, nil
+}
+
+// HasMembershipOutside reports whether the user also works for a
+// restaurant of another organization. Admins of one organization must not
+// be able to lock such a user out of the other.
+func (rr *RestaurantRepository) HasMembershipOutside(userId, organizationId uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1
+			FROM restaurant_memberships m
+			JOIN restaurants r ON r.id = m.restaurant_id
+			WHERE m.user_id = $1 AND r.organization_id <> $2
+		)`
+
+	var exists bool
+
+	if err := rr.db.QueryRow(query, userId, organizationId).Scan(&exists); err != nil {
+		log.Printf("ERROR: Failed to check user memberships: %v", err)
+		return false, fmt.Errorf("error checking user memberships: %v", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+)
+
+type RoleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) *RoleRepository {
+	return &RoleRepository{db}
+}
+
+func (rr *RoleRepository) GetPermissionsByRole(role models.Role) ([]string, error) {
+	query := `SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission`
+
+	rows, err := rr.db.Query(query, role)
+	if err != nil {
+		log.Printf("ERROR: Failed to get permissions by role: %v", err)
+		return nil, fmt.Errorf("error getting permissions by role: %v", err)
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			log.Printf("ERROR: Failed to scan permission: %v", err)
+			return nil, fmt.Errorf("error scanning permission: %v", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+func (rr *RoleRepository) RoleHasPermission(role models.Role, permission string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM role_permissions WHERE role = $1 AND permission = $2)`
+
+	var exists bool
+
+	err := rr.db.QueryRow(query, role, permission).Scan(&exists)
+	if err != nil {
+		log.Printf("ERROR: Failed to check role permission: %v", err)
+		return false, fmt.Errorf("error checking role permission: %v", err)
+	}
+
+	return exists, nil
+}
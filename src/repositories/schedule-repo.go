@@ -0,0 +1,329 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const menuColumns = `id, restaurant_id, name, description, position, is_available, created_at, updated_at`
+
+type ScheduleRepository struct {
+	db *sql.DB
+}
+
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+	return &ScheduleRepository{db}
+}
+
+func scanMenu(row interface{ Scan(...any) error }) (*models.Menu, error) {
+	menu := &models.Menu{
+		CategoryIds: []uuid.UUID{},
+		Windows:     []*models.MenuWindow{},
+		Overrides:   []*models.MenuOverride{},
+	}
+
+	err := row.Scan(&menu.Id, &menu.RestaurantId, &menu.Name, &menu.Description, &menu.Position, &menu.IsAvailable,
+		&menu.CreatedAt, &menu.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return menu, nil
+}
+
+// CreateMenu appends the menu after the others unless a position is given.
+func (sr *ScheduleRepository) CreateMenu(menu *models.Menu, position *int) error {
+	query := `
+		INSERT INTO menus (restaurant_id, name, description, position, is_available, created_at, updated_at)
+		VALUES ($1, $2, $3,
+			COALESCE($4::int, (SELECT COALESCE(MAX(position) + 1, 0) FROM menus WHERE restaurant_id = $1)),
+			$5, $6, $6)
+		RETURNING id, position`
+
+	menu.CreatedAt = time.Now()
+	menu.UpdatedAt = menu.CreatedAt
+
+	err := sr.db.QueryRow(query, menu.RestaurantId, menu.Name, menu.Description, position, menu.IsAvailable, menu.CreatedAt).
+		Scan(&menu.Id, &menu.Position)
+	if err != nil {
+		log.Printf("ERROR: Failed to create menu: %v", err)
+		return fmt.Errorf("error creating menu: %v", err)
+	}
+
+	return nil
+}
+
+// GetMenu returns the menu with its categories, windows and overrides.
+func (sr *ScheduleRepository) GetMenu(restaurantId, id uuid.UUID) (*models.Menu, error) {
+	query := `SELECT ` + menuColumns + ` FROM menus WHERE id = $1 AND restaurant_id = $2`
+
+	menu, err := scanMenu(sr.db.QueryRow(query, id, restaurantId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get menu: %v", err)
+		return nil, fmt.Errorf("error getting menu: %v", err)
+	}
+
+	if err := sr.loadSchedules([]*models.Menu{menu}); err != nil {
+		return nil, err
+	}
+
+	return menu, nil
+}
+
+// GetMenus returns every menu of the restaurant in display order, with
+// categories, windows and overrides.
+func (sr *ScheduleRepository) GetMenus(restaurantId uuid.UUID) ([]*models.Menu, error) {
+	query := `SELECT ` + menuColumns + ` FROM menus WHERE restaurant_id = $1 ORDER BY position, name, id`
+
+	rows, err := sr.db.Query(query, restaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to get menus: %v", err)
+		return nil, fmt.Errorf("error getting menus: %v", err)
+	}
+	defer rows.Close()
+
+	menus := []*models.Menu{}
+	for rows.Next() {
+		menu, err := scanMenu(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan menu: %v", err)
+			return nil, fmt.Errorf("error scanning menu: %v", err)
+		}
+		menus = append(menus, menu)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := sr.loadSchedules(menus); err != nil {
+		return nil, err
+	}
+
+	return menus, nil
+}
+
+func (sr *ScheduleRepository) loadSchedules(menus []*models.Menu) error {
+	if len(menus) == 0 {
+		return nil
+	}
+
+	byId := make(map[uuid.UUID]*models.Menu, len(menus))
+	ids := make([]string, len(menus))
+	for i, menu := range menus {
+		byId[menu.Id] = menu
+		ids[i] = menu.Id.String()
+	}
+
+	rows, err := sr.db.Query(`
+		SELECT menu_id, category_id FROM menu_categories_menus
+		WHERE menu_id = ANY($1::uuid[])
+		ORDER BY position, category_id`,
+		pq.Array(ids))
+	if err != nil {
+		log.Printf("ERROR: Failed to get menu categories: %v", err)
+		return fmt.Errorf("error getting menu categories: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var menuId, categoryId uuid.UUID
+		if err := rows.Scan(&menuId, &categoryId); err != nil {
+			log.Printf("ERROR: Failed to scan menu category: %v", err)
+			return fmt.Errorf("error scanning menu category: %v", err)
+		}
+		byId[menuId].CategoryIds = append(byId[menuId].CategoryIds, categoryId)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = sr.db.Query(`
+		SELECT menu_id, weekday, start_minute, end_minute FROM menu_windows
+		WHERE menu_id = ANY($1::uuid[])
+		ORDER BY weekday, start_minute, end_minute`,
+		pq.Array(ids))
+	if err != nil {
+		log.Printf("ERROR: Failed to get menu windows: %v", err)
+		return fmt.Errorf("error getting menu windows: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var menuId uuid.UUID
+		window := &models.MenuWindow{}
+		if err := rows.Scan(&menuId, &window.Weekday, &window.Start, &window.End); err != nil {
+			log.Printf("ERROR: Failed to scan menu window: %v", err)
+			return fmt.Errorf("error scanning menu window: %v", err)
+		}
+		byId[menuId].Windows = append(byId[menuId].Windows, window)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = sr.db.Query(`
+		SELECT menu_id, to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD'), is_available,
+			start_minute, end_minute, note
+		FROM menu_overrides
+		WHERE menu_id = ANY($1::uuid[])
+		ORDER BY start_date, end_date`,
+		pq.Array(ids))
+	if err != nil {
+		log.Printf("ERROR: Failed to get menu overrides: %v", err)
+		return fmt.Errorf("error getting menu overrides: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var menuId uuid.UUID
+		override := &models.MenuOverride{}
+		err := rows.Scan(&menuId, &override.StartDate, &override.EndDate, &override.IsAvailable,
+			&override.Start, &override.End, &override.Note)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan menu override: %v", err)
+			return fmt.Errorf("error scanning menu override: %v", err)
+		}
+		byId[menuId].Overrides = append(byId[menuId].Overrides, override)
+	}
+
+	return rows.Err()
+}
+
+func (sr *ScheduleRepository) UpdateMenu(menu *models.Menu) error {
+	query := `
+		UPDATE menus SET name = $1, description = $2, position = $3, is_available = $4, updated_at = $5
+		WHERE id = $6 AND restaurant_id = $7`
+
+	menu.UpdatedAt = time.Now()
+
+	_, err := sr.db.Exec(query, menu.Name, menu.Description, menu.Position, menu.IsAvailable, menu.UpdatedAt,
+		menu.Id, menu.RestaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to update menu: %v", err)
+		return fmt.Errorf("error updating menu: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteMenu leaves the categories in place. Those in no other menu can
+// then be ordered at any time.
+func (sr *ScheduleRepository) DeleteMenu(restaurantId, id uuid.UUID) (bool, error) {
+	query := `DELETE FROM menus WHERE id = $1 AND restaurant_id = $2`
+
+	return deleteTranslated(sr.db, "menu", query, []any{id, restaurantId}, func(tx *sql.Tx) error {
+		return deleteTranslations(tx, models.TranslationEntityMenu, id)
+	})
+}
+
+// SetMenuCategories replaces the categories of the menu, shown in the order
+// given. It reports false and changes nothing when an id is not one of the
+// restaurant's categories or appears twice.
+func (sr *ScheduleRepository) SetMenuCategories(restaurantId, menuId uuid.UUID, categoryIds []uuid.UUID) (bool, error) {
+	ids := make([]string, len(categoryIds))
+	for i, id := range categoryIds {
+		ids[i] = id.String()
+	}
+
+	tx, err := sr.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to set menu categories: %v", err)
+		return false, fmt.Errorf("error setting menu categories: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM menu_categories_menus WHERE menu_id = $1`, menuId); err != nil {
+		log.Printf("ERROR: Failed to set menu categories: %v", err)
+		return false, fmt.Errorf("error setting menu categories: %v", err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO menu_categories_menus (menu_id, category_id, position)
+		SELECT $1, c.id, o.position - 1
+		FROM (SELECT DISTINCT ON (id) id, position FROM unnest($3::uuid[]) WITH ORDINALITY AS u(id, position)) o
+		JOIN menu_categories c ON c.id = o.id
+		WHERE c.restaurant_id = $2`,
+		menuId, restaurantId, pq.Array(ids))
+	if err != nil {
+		log.Printf("ERROR: Failed to set menu categories: %v", err)
+		return false, fmt.Errorf("error setting menu categories: %v", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if inserted != int64(len(categoryIds)) {
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to set menu categories: %v", err)
+		return false, fmt.Errorf("error setting menu categories: %v", err)
+	}
+
+	return true, nil
+}
+
+// SetSchedule replaces all windows and overrides of the menu.
+func (sr *ScheduleRepository) SetSchedule(menuId uuid.UUID, windows []*models.MenuWindow, overrides []*models.MenuOverride) error {
+	tx, err := sr.db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to set menu schedule: %v", err)
+		return fmt.Errorf("error setting menu schedule: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM menu_windows WHERE menu_id = $1`, menuId); err != nil {
+		log.Printf("ERROR: Failed to set menu schedule: %v", err)
+		return fmt.Errorf("error setting menu schedule: %v", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM menu_overrides WHERE menu_id = $1`, menuId); err != nil {
+		log.Printf("ERROR: Failed to set menu schedule: %v", err)
+		return fmt.Errorf("error setting menu schedule: %v", err)
+	}
+
+	for _, window := range windows {
+		_, err := tx.Exec(`
+			INSERT INTO menu_windows (menu_id, weekday, start_minute, end_minute)
+			VALUES ($1, $2, $3, $4)`,
+			menuId, window.Weekday, window.Start, window.End)
+		if err != nil {
+			log.Printf("ERROR: Failed to set menu schedule: %v", err)
+			return fmt.Errorf("error setting menu schedule: %v", err)
+		}
+	}
+
+	for _, override := range overrides {
+		_, err := tx.Exec(`
+			INSERT INTO menu_overrides (menu_id, start_date, end_date, is_available, start_minute, end_minute, note)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			menuId, override.StartDate, override.EndDate, override.IsAvailable, override.Start, override.End, override.Note)
+		if err != nil {
+			log.Printf("ERROR: Failed to set menu schedule: %v", err)
+			return fmt.Errorf("error setting menu schedule: %v", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE menus SET updated_at = $1 WHERE id = $2`, time.Now(), menuId); err != nil {
+		log.Printf("ERROR: Failed to set menu schedule: %v", err)
+		return fmt.Errorf("error setting menu schedule: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to set menu schedule: %v", err)
+		return fmt.Errorf("error setting menu schedule: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db}
+}
+
+func (sr *SessionRepository) CreateSession(session *models.Session) error {
+	query := `
+		INSERT INTO sessions (user_id, token_hash, user_agent, ip_address, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	now := time.Now()
+	session.CreatedAt = now
+	session.LastSeenAt = now
+
+	err := sr.db.QueryRow(query, session.UserId, session.TokenHash, session.UserAgent, session.IPAddress,
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt).Scan(&session.Id)
+
+	if err != nil {
+		log.Printf("ERROR: Failed to create session: %v", err)
+		return fmt.Errorf("error creating session: %v", err)
+	}
+
+	return nil
+}
+
+func (sr *SessionRepository) GetActiveSessionByTokenHash(tokenHash string) (*models.Session, error) {
+	query := `
+		SELECT id, user_id, token_hash, user_agent, ip_address, created_at, last_seen_at, expires_at
+		FROM sessions
+		WHERE token_hash = $1 AND expires_at > $2`
+
+	session := &models.Session{}
+
+	err := sr.db.QueryRow(query, tokenHash, time.Now()).Scan(&session.Id, &session.UserId, &session.TokenHash,
+		&session.UserAgent, &session.IPAddress, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get session by token: %v", err)
+		return nil, fmt.Errorf("error getting session by token: %v", err)
+	}
+
+	return session, nil
+}
+
+func (sr *SessionRepository) TouchSession(id uuid.UUID, lastSeenAt, expiresAt time.Time) error {
+	query := `UPDATE sessions SET last_seen_at = $1, expires_at = $2 WHERE id = $3`
+
+	if _, err := sr.db.Exec(query, lastSeenAt, expiresAt, id); err != nil {
+		log.Printf("ERROR: Failed to touch session: %v", err)
+		return fmt.Errorf("error touching session: %v", err)
+	}
+
+	return nil
+}
+
+func (sr *SessionRepository) DeleteSession(id uuid.UUID) error {
+	query := `DELETE FROM sessions WHERE id = $1`
+
+	if _, err := sr.db.Exec(query, id); err != nil {
+		log.Printf("ERROR: Failed to delete session: %v", err)
+		return fmt.Errorf("error deleting session: %v", err)
+	}
+
+	return nil
+}
+
+func (sr *SessionRepository) GetActiveSessionsByUserId(userId uuid.UUID) ([]*models.Session, error) {
+	query := `
+		SELECT id, user_id, token_hash, user_agent, ip_address, created_at, last_seen_at, expires_at
+		FROM sessions
+		WHERE user_id = $1 AND expires_at > $2
+		ORDER BY last_seen_at DESC`
+
+	rows, err := sr.db.Query(query, userId, time.Now())
+	if err != nil {
+		log.Printf("ERROR: Failed to get sessions by user id: %v", err)
+		return nil, fmt.Errorf("error getting sessions by user id: %v", err)
+	}
+	defer rows.Close()
+
+	sessions := []*models.Session{}
+	for rows.Next() {
+		session := &models.Session{}
+		if err := rows.Scan(&session.Id, &session.UserId, &session.TokenHash, &session.UserAgent,
+			&session.IPAddress, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt); err != nil {
+			log.Printf("ERROR: Failed to scan session: %v", err)
+			return nil, fmt.Errorf("error scanning session: %v", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// DeleteUserSession removes a session only if it belongs to the given user.
+func (sr *SessionRepository) DeleteUserSession(id, userId uuid.UUID) (bool, error) {
+	query := `DELETE FROM sessions WHERE id = $1 AND user_id = $2`
+
+	result, err := sr.db.Exec(query, id, userId)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete user session: %v", err)
+		return false, fmt.Errorf("error deleting user session: %v", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+func (sr *SessionRepository) DeleteSessionsByUserId(userId uuid.UUID) (int64, error) {
+	query := `DELETE FROM sessions WHERE user_id = $1`
+
+	result, err := sr.db.Exec(query, userId)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete sessions by user id: %v", err)
+		return 0, fmt.Errorf("error deleting sessions by user id: %v", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (sr *SessionRepository) DeleteExpiredSessions() (int64, error) {
+	query := `DELETE FROM sessions WHERE expires_at <= $1`
+
+	result, err := sr.db.Exec(query, time.Now())
+	if err != nil {
+		log.Printf("ERROR: Failed to delete expired sessions: %v", err)
+		return 0, fmt.Errorf("error deleting expired sessions: %v", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,321 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const translationColumns = `t.entity_type, t.entity_id, t.field, t.locale, t.value, t.source, t.updated_at`
+
+// translatableTexts lists every text of the restaurant $1 that takes
+// translations. Keep it in line with models.TranslatableFields. Empty
+// descriptions have nothing to translate.
+const translatableTexts = `
+	SELECT 'menu_category' AS entity_type, id AS entity_id, 'name' AS field, name AS source
+	FROM menu_categories WHERE restaurant_id = $1
+	UNION ALL
+	SELECT 'menu_category', id, 'description', description
+	FROM menu_categories WHERE restaurant_id = $1 AND description <> ''
+	UNION ALL
+	SELECT 'menu_item', id, 'name', name
+	FROM menu_items WHERE restaurant_id = $1
+	UNION ALL
+	SELECT 'menu_item', id, 'description', description
+	FROM menu_items WHERE restaurant_id = $1 AND description <> ''
+	UNION ALL
+	SELECT 'modifier_group', id, 'name', name
+	FROM modifier_groups WHERE restaurant_id = $1
+	UNION ALL
+	SELECT 'modifier_option', o.id, 'name', o.name
+	FROM modifier_options o JOIN modifier_groups g ON g.id = o.group_id WHERE g.restaurant_id = $1
+	UNION ALL
+	SELECT 'menu', id, 'name', name
+	FROM menus WHERE restaurant_id = $1
+	UNION ALL
+	SELECT 'menu', id, 'description', description
+	FROM menus WHERE restaurant_id = $1 AND description <> ''`
+
+type TranslationRepository struct {
+	db *sql.DB
+}
+
+func NewTranslationRepository(db *sql.DB) *TranslationRepository {
+	return &TranslationRepository{db}
+}
+
+func scanTranslation(row interface{ Scan(...any) error }) (*models.Translation, error) {
+	translation := &models.Translation{}
+
+	err := row.Scan(&translation.EntityType, &translation.EntityId, &translation.Field, &translation.Locale,
+		&translation.Value, &translation.Source, &translation.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+// GetSource returns the current text of a translatable field, or false
+// when the restaurant has no such text.
+func (tr *TranslationRepository) GetSource(restaurantId uuid.UUID, entityType string, entityId uuid.UUID, field string) (string, bool, error) {
+	query := `
+		SELECT s.source FROM (` + translatableTexts + `) s
+		WHERE s.entity_type = $2 AND s.entity_id = $3 AND s.field = $4`
+
+	var source string
+	err := tr.db.QueryRow(query, restaurantId, entityType, entityId, field).Scan(&source)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+
+		log.Printf("ERROR: Failed to get translation source: %v", err)
+		return "", false, fmt.Errorf("error getting translation source: %v", err)
+	}
+
+	return source, true, nil
+}
+
+// GetTranslations lists translations of texts that still exist, ordered by
+// locale and entity.
+func (tr *TranslationRepository) GetTranslations(filter models.TranslationFilter) ([]*models.Translation, error) {
+	conditions := ""
+	args := []any{filter.RestaurantId}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions += fmt.Sprintf(" AND "+condition, len(args))
+	}
+
+	if filter.EntityType != "" {
+		add("t.entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityId != nil {
+		add("t.entity_id = $%d", *filter.EntityId)
+	}
+	if filter.Locale != "" {
+		add("t.locale = $%d", filter.Locale)
+	}
+
+	query := `
+		SELECT ` + translationColumns + `
+		FROM translations t
+		JOIN (` + translatableTexts + `) s ON s.entity_type = t.entity_type AND s.entity_id = t.entity_id AND s.field = t.field
+		WHERE t.restaurant_id = $1` + conditions + `
+		ORDER BY t.locale, t.entity_type, t.entity_id, t.field`
+
+	return tr.queryTranslations(query, args...)
+}
+
+// GetValues returns the translations of the entities into any of the
+// locales, for translating responses.
+func (tr *TranslationRepository) GetValues(restaurantId uuid.UUID, entityIds []uuid.UUID, locales []string) ([]*models.Translation, error) {
+	if len(entityIds) == 0 || len(locales) == 0 {
+		return []*models.Translation{}, nil
+	}
+
+	ids := make([]string, len(entityIds))
+	for i, id := range entityIds {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + translationColumns + `
+		FROM translations t
+		WHERE t.restaurant_id = $1 AND t.entity_id = ANY($2::uuid[]) AND t.locale = ANY($3::text[])`
+
+	return tr.queryTranslations(query, restaurantId, pq.Array(ids), pq.Array(locales))
+}
+
+func (tr *TranslationRepository) queryTranslations(query string, args ...any) ([]*models.Translation, error) {
+	rows, err := tr.db.Query(query, args...)
+	if err != nil {
+		log.Printf("ERROR: Failed to get translations: %v", err)
+		return nil, fmt.Errorf("error getting translations: %v", err)
+	}
+	defer rows.Close()
+
+	translations := []*models.Translation{}
+	for rows.Next() {
+		translation, err := scanTranslation(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan translation: %v", err)
+			return nil, fmt.Errorf("error scanning translation: %v", err)
+		}
+		translations = append(translations, translation)
+	}
+
+	return translations, rows.Err()
+}
+
+// SetTranslation adds the translation or replaces the one of the same
+// field and locale.
+func (tr *TranslationRepository) SetTranslation(restaurantId uuid.UUID, translation *models.Translation) error {
+	query := `
+		INSERT INTO translations (restaurant_id, entity_type, entity_id, field, locale, value, source, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (entity_type, entity_id, field, locale)
+		DO UPDATE SET value = EXCLUDED.value, source = EXCLUDED.source, updated_at = EXCLUDED.updated_at`
+
+	translation.UpdatedAt = time.Now()
+
+	_, err := tr.db.Exec(query, restaurantId, translation.EntityType, translation.EntityId, translation.Field,
+		translation.Locale, translation.Value, translation.Source, translation.UpdatedAt)
+	if err != nil {
+		log.Printf("ERROR: Failed to set translation: %v", err)
+		return fmt.Errorf("error setting translation: %v", err)
+	}
+
+	return nil
+}
+
+func (tr *TranslationRepository) GetTranslation(restaurantId uuid.UUID, entityType string, entityId uuid.UUID, field, locale string) (*models.Translation, error) {
+	query := `
+		SELECT ` + translationColumns + ` FROM translations t
+		WHERE t.restaurant_id = $1 AND t.entity_type = $2 AND t.entity_id = $3 AND t.field = $4 AND t.locale = $5`
+
+	translation, err := scanTranslation(tr.db.QueryRow(query, restaurantId, entityType, entityId, field, locale))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get translation: %v", err)
+		return nil, fmt.Errorf("error getting translation: %v", err)
+	}
+
+	return translation, nil
+}
+
+func (tr *TranslationRepository) DeleteTranslation(restaurantId uuid.UUID, entityType string, entityId uuid.UUID, field, locale string) (bool, error) {
+	result, err := tr.db.Exec(`
+		DELETE FROM translations
+		WHERE restaurant_id = $1 AND entity_type = $2 AND entity_id = $3 AND field = $4 AND locale = $5`,
+		restaurantId, entityType, entityId, field, locale)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete translation: %v", err)
+		return false, fmt.Errorf("error deleting translation: %v", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+// GetLocales returns the locales the restaurant has translations in.
+func (tr *TranslationRepository) GetLocales(restaurantId uuid.UUID) ([]string, error) {
+	rows, err := tr.db.Query(`SELECT DISTINCT locale FROM translations WHERE restaurant_id = $1 ORDER BY locale`, restaurantId)
+	if err != nil {
+		log.Printf("ERROR: Failed to get translation locales: %v", err)
+		return nil, fmt.Errorf("error getting translation locales: %v", err)
+	}
+	defer rows.Close()
+
+	locales := []string{}
+	for rows.Next() {
+		var locale string
+		if err := rows.Scan(&locale); err != nil {
+			log.Printf("ERROR: Failed to scan translation locale: %v", err)
+			return nil, fmt.Errorf("error scanning translation locale: %v", err)
+		}
+		locales = append(locales, locale)
+	}
+
+	return locales, rows.Err()
+}
+
+// GetMissing lists, for each locale, the texts without a translation and
+// those whose translation was made from a text that has changed since.
+func (tr *TranslationRepository) GetMissing(restaurantId uuid.UUID, locales []string) ([]*models.MissingTranslation, error) {
+	query := `
+		SELECT s.entity_type, s.entity_id, s.field, l.locale, s.source, t.source IS NOT NULL
+		FROM (` + translatableTexts + `) s
+		CROSS JOIN unnest($2::text[]) AS l(locale)
+		LEFT JOIN translations t
+			ON t.entity_type = s.entity_type AND t.entity_id = s.entity_id AND t.field = s.field AND t.locale = l.locale
+		WHERE t.source IS NULL OR t.source <> s.source
+		ORDER BY l.locale, s.entity_type, s.source, s.entity_id, s.field`
+
+	rows, err := tr.db.Query(query, restaurantId, pq.Array(locales))
+	if err != nil {
+		log.Printf("ERROR: Failed to get missing translations: %v", err)
+		return nil, fmt.Errorf("error getting missing translations: %v", err)
+	}
+	defer rows.Close()
+
+	missing := []*models.MissingTranslation{}
+	for rows.Next() {
+		entry := &models.MissingTranslation{}
+		err := rows.Scan(&entry.EntityType, &entry.EntityId, &entry.Field, &entry.Locale, &entry.Source, &entry.Outdated)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan missing translation: %v", err)
+			return nil, fmt.Errorf("error scanning missing translation: %v", err)
+		}
+		missing = append(missing, entry)
+	}
+
+	return missing, rows.Err()
+}
+
+// deleteTranslated runs the delete query after clear has removed the
+// translations of the rows it deletes, in one transaction. It reports
+// whether a row was deleted.
+func deleteTranslated(db *sql.DB, what, query string, args []any, clear func(tx *sql.Tx) error) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("ERROR: Failed to delete %s: %v", what, err)
+		return false, fmt.Errorf("error deleting %s: %v", what, err)
+	}
+	defer tx.Rollback()
+
+	if err := clear(tx); err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete %s: %v", what, err)
+		return false, fmt.Errorf("error deleting %s: %v", what, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if deleted == 0 {
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to delete %s: %v", what, err)
+		return false, fmt.Errorf("error deleting %s: %v", what, err)
+	}
+
+	return true, nil
+}
+
+// deleteTranslations removes the translations of entities that are being
+// deleted, see deleteTranslated.
+func deleteTranslations(tx *sql.Tx, entityType string, entityIds ...uuid.UUID) error {
+	ids := make([]string, len(entityIds))
+	for i, id := range entityIds {
+		ids[i] = id.String()
+	}
+
+	_, err := tx.Exec(`DELETE FROM translations WHERE entity_type = $1 AND entity_id = ANY($2::uuid[])`, entityType, pq.Array(ids))
+	if err != nil {
+		log.Printf("ERROR: Failed to delete translations: %v", err)
+		return fmt.Errorf("error deleting translations: %v", err)
+	}
+
+	return nil
+}
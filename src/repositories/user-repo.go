@@ -5,11 +5,26 @@ import (
 	"fmt"
 	"log"
 	"restaurant-backend/src/models"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Email and phone are NULL when unused, so both stay unique among the
+// accounts that have one. They read as empty strings.
+const userColumns = `id, name, COALESCE(email, ''), COALESCE(phone, ''), account_type, password, role, email_verified_at,
+	totp_secret, totp_enabled_at, totp_last_step, is_active, deleted_at, created_at, updated_at`
+
+// memberColumns reads a user through their membership, with the role they
+// hold in that restaurant instead of users.role.
+const memberColumns = `u.id, u.name, COALESCE(u.email, ''), COALESCE(u.phone, ''), u.account_type, u.password, m.role, u.email_verified_at,
+	u.totp_secret, u.totp_enabled_at, u.totp_last_step, u.is_active, u.deleted_at, u.created_at, u.updated_at`
+
+// UserRepository looks accounts up by id or email without a restaurant,
+// since signing in happens before one is chosen. Everything staff admins
+// do goes through the restaurant scoped methods, which only see members of
+// that restaurant.
 type UserRepository struct {
 	db *sql.DB
 }
@@ -18,19 +33,43 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db}
 }
 
+func scanUser(row interface{ Scan(...any) error }) (*models.User, error) {
+	user := &models.User{}
+
+	err := row.Scan(&user.Id, &user.Name, &user.Email, &user.Phone, &user.AccountType, &user.Password, &user.Role, &user.EmailVerifiedAt,
+		&user.TOTPSecret, &user.TOTPEnabledAt, &user.TOTPLastStep, &user.IsActive, &user.DeletedAt, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func (ur *UserRepository) CreateUser(user *models.User) error {
+	return insertUser(ur.db, user)
+}
+
+func insertUser(db interface {
+	QueryRow(string, ...any) *sql.Row
+}, user *models.User) error {
 	user.Id = uuid.New()
 
 	query := `
-		INSERT INTO users (name, email, password, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (name, email, phone, account_type, password, role, email_verified_at, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7, $8, $9)
 		RETURNING id`
 
 	now := time.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
+	user.IsActive = true
 
-	err := ur.db.QueryRow(query, user.Name, user.Email, user.Password, user.CreatedAt, user.UpdatedAt).Scan(&user.Id)
+	if user.AccountType == "" {
+		user.AccountType = models.AccountTypeStaff
+	}
+
+	err := db.QueryRow(query, user.Name, user.Email, user.Phone, user.AccountType, user.Password, user.Role, user.EmailVerifiedAt,
+		user.CreatedAt, user.UpdatedAt).Scan(&user.Id)
 
 	if err != nil {
 		log.Printf("ERROR: Failed to create user: %v", err)
@@ -41,11 +80,9 @@ func (ur *UserRepository) CreateUser(user *models.User) error {
 }
 
 func (ur *UserRepository) GetUserById(id string) (*models.User, error) {
-	query := `SELECT id, name, email, password, created_at, updated_at FROM users WHERE email = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND deleted_at IS NULL`
 
-	user := &models.User{}
-
-	err := ur.db.QueryRow(query, id).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt)
+	user, err := scanUser(ur.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -59,8 +96,9 @@ func (ur *UserRepository) GetUserById(id string) (*models.User, error) {
 
 }
 
+// UserExists ignores soft deleted accounts, their email is free again.
 func (ur *UserRepository) UserExists(email string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND deleted_at IS NULL)`
 
 	var exists bool
 
@@ -74,11 +112,9 @@ func (ur *UserRepository) UserExists(email string) (bool, error) {
 }
 
 func (ur *UserRepository) GetUserByEmail(email string) (*models.User, error) {
-	query := `SELECT * FROM users WHERE email = $1`
-
-	user := &models.User{}
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1 AND deleted_at IS NULL`
 
-	err := ur.db.QueryRow(query, email).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt)
+	user, err := scanUser(ur.db.QueryRow(query, email))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -91,3 +127,273 @@ func (ur *UserRepository) GetUserByEmail(email string) (*models.User, error) {
 
 	return user, nil
 }
+
+func (ur *UserRepository) PhoneExists(phone string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE phone = $1 AND deleted_at IS NULL)`
+
+	var exists bool
+
+	err := ur.db.QueryRow(query, phone).Scan(&exists)
+	if err != nil {
+		log.Printf("ERROR: Failed to check if phone exists: %v", err)
+		return false, fmt.Errorf("error checking if phone exists: %v", err)
+	}
+
+	return exists, nil
+}
+
+// GetCustomerByLogin finds a customer account by email or phone number,
+// whichever the customer signs in with.
+func (ur *UserRepository) GetCustomerByLogin(login string) (*models.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE (email = $1 OR phone = $1) AND account_type = $2 AND deleted_at IS NULL`
+
+	user, err := scanUser(ur.db.QueryRow(query, login, models.AccountTypeCustomer))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get customer by login: %v", err)
+		return nil, fmt.Errorf("error getting customer by login: %v", err)
+	}
+
+	return user, nil
+}
+
+func (ur *UserRepository) UpdatePassword(id uuid.UUID, password string) error {
+	query := `UPDATE users SET password = $1, updated_at = $2 WHERE id = $3`
+
+	if _, err := ur.db.Exec(query, password, time.Now(), id); err != nil {
+		log.Printf("ERROR: Failed to update user password: %v", err)
+		return fmt.Errorf("error updating user password: %v", err)
+	}
+
+	return nil
+}
+
+// MarkEmailVerified only succeeds while the user still has the address
+// the verification token was issued for.
+func (ur *UserRepository) MarkEmailVerified(id uuid.UUID, email string) (bool, error) {
+	query := `UPDATE users SET email_verified_at = $1, updated_at = $1 WHERE id = $2 AND email = $3`
+
+	result, err := ur.db.Exec(query, time.Now(), id, email)
+	if err != nil {
+		log.Printf("ERROR: Failed to mark email verified: %v", err)
+		return false, fmt.Errorf("error marking email verified: %v", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+// SetPendingTOTPSecret stores a new secret while two-factor is still off.
+func (ur *UserRepository) SetPendingTOTPSecret(id uuid.UUID, secret string) error {
+	query := `UPDATE users SET totp_secret = $1, updated_at = $2 WHERE id = $3 AND totp_enabled_at IS NULL`
+
+	if _, err := ur.db.Exec(query, secret, time.Now(), id); err != nil {
+		log.Printf("ERROR: Failed to set totp secret: %v", err)
+		return fmt.Errorf("error setting totp secret: %v", err)
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) EnableTOTP(id uuid.UUID) error {
+	query := `UPDATE users SET totp_enabled_at = $1, updated_at = $1 WHERE id = $2 AND totp_secret <> ''`
+
+	if _, err := ur.db.Exec(query, time.Now(), id); err != nil {
+		log.Printf("ERROR: Failed to enable totp: %v", err)
+		return fmt.Errorf("error enabling totp: %v", err)
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) DisableTOTP(id uuid.UUID) error {
+	query := `UPDATE users SET totp_secret = '', totp_enabled_at = NULL, totp_last_step = 0, updated_at = $1 WHERE id = $2`
+
+	if _, err := ur.db.Exec(query, time.Now(), id); err != nil {
+		log.Printf("ERROR: Failed to disable totp: %v", err)
+		return fmt.Errorf("error disabling totp: %v", err)
+	}
+
+	return nil
+}
+
+// AdvanceTOTPStep records the last accepted time step so a code cannot be
+// replayed. It reports false when the step was already used.
+func (ur *UserRepository) AdvanceTOTPStep(id uuid.UUID, step int64) (bool, error) {
+	query := `UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1`
+
+	result, err := ur.db.Exec(query, step, id)
+	if err != nil {
+		log.Printf("ERROR: Failed to advance totp step: %v", err)
+		return false, fmt.Errorf("error advancing totp step: %v", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+func (ur *UserRepository) UpdateProfile(user *models.User) error {
+	query := `UPDATE users SET name = $1, updated_at = $2 WHERE id = $3`
+
+	user.UpdatedAt = time.Now()
+
+	if _, err := ur.db.Exec(query, user.Name, user.UpdatedAt, user.Id); err != nil {
+		log.Printf("ERROR: Failed to update user profile: %v", err)
+		return fmt.Errorf("error updating user profile: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateEmail switches the account to a new address that has just been
+// verified.
+func (ur *UserRepository) UpdateEmail(id uuid.UUID, email string) error {
+	query := `UPDATE users SET email = $1, email_verified_at = $2, updated_at = $2 WHERE id = $3`
+
+	if _, err := ur.db.Exec(query, email, time.Now(), id); err != nil {
+		log.Printf("ERROR: Failed to update user email: %v", err)
+		return fmt.Errorf("error updating user email: %v", err)
+	}
+
+	return nil
+}
+
+// ListUsers returns one page of the restaurant's members whose name or
+// email contains the search term, together with the total number of matches.
+func (ur *UserRepository) ListUsers(restaurantId uuid.UUID, search string, limit, offset int) ([]*models.User, int, error) {
+	pattern := "%" + escapeLike(search) + "%"
+
+	var total int
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM users u
+		JOIN restaurant_memberships m ON m.user_id = u.id AND m.restaurant_id = $1
+		WHERE u.deleted_at IS NULL AND (u.name ILIKE $2 OR u.email ILIKE $2)`
+	if err := ur.db.QueryRow(countQuery, restaurantId, pattern).Scan(&total); err != nil {
+		log.Printf("ERROR: Failed to count users: %v", err)
+		return nil, 0, fmt.Errorf("error counting users: %v", err)
+	}
+
+	query := `
+		SELECT ` + memberColumns + `
+		FROM users u
+		JOIN restaurant_memberships m ON m.user_id = u.id AND m.restaurant_id = $1
+		WHERE u.deleted_at IS NULL AND (u.name ILIKE $2 OR u.email ILIKE $2)
+		ORDER BY u.name, u.email
+		LIMIT $3 OFFSET $4`
+
+	rows, err := ur.db.Query(query, restaurantId, pattern, limit, offset)
+	if err != nil {
+		log.Printf("ERROR: Failed to list users: %v", err)
+		return nil, 0, fmt.Errorf("error listing users: %v", err)
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan user: %v", err)
+			return nil, 0, fmt.Errorf("error scanning user: %v", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+// GetRestaurantUser returns nil when the user is not a member of the
+// restaurant, exactly as if the account did not exist.
+func (ur *UserRepository) GetRestaurantUser(restaurantId, id uuid.UUID) (*models.User, error) {
+	query := `
+		SELECT ` + memberColumns + `
+		FROM users u
+		JOIN restaurant_memberships m ON m.user_id = u.id AND m.restaurant_id = $1
+		WHERE u.id = $2 AND u.deleted_at IS NULL`
+
+	user, err := scanUser(ur.db.QueryRow(query, restaurantId, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Printf("ERROR: Failed to get restaurant user: %v", err)
+		return nil, fmt.Errorf("error getting restaurant user: %v", err)
+	}
+
+	return user, nil
+}
+
+// UpdateRole changes the user's role in one restaurant only.
+func (ur *UserRepository) UpdateRole(restaurantId, id uuid.UUID, role models.Role) error {
+	query := `UPDATE restaurant_memberships SET role = $1 WHERE restaurant_id = $2 AND user_id = $3`
+
+	if _, err := ur.db.Exec(query, role, restaurantId, id); err != nil {
+		log.Printf("ERROR: Failed to update user role: %v", err)
+		return fmt.Errorf("error updating user role: %v", err)
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) SetActive(id uuid.UUID, active bool) error {
+	query := `UPDATE users SET is_active = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+
+	if _, err := ur.db.Exec(query, active, time.Now(), id); err != nil {
+		log.Printf("ERROR: Failed to update user status: %v", err)
+		return fmt.Errorf("error updating user status: %v", err)
+	}
+
+	return nil
+}
+
+// SoftDeleteUser hides the user from every lookup but keeps the row, and
+// with it the email, so history stays intact. The email can be used by a
+// new account afterwards.
+func (ur *UserRepository) SoftDeleteUser(id uuid.UUID) error {
+	query := `UPDATE users SET deleted_at = $1, is_active = FALSE, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	if _, err := ur.db.Exec(query, time.Now(), id); err != nil {
+		log.Printf("ERROR: Failed to delete user: %v", err)
+		return fmt.Errorf("error deleting user: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteEndedGuests removes guest accounts created before createdBefore
+// that have no session left. Guests cannot sign in again, so nothing can
+// use them any more.
+func (ur *UserRepository) DeleteEndedGuests(createdBefore time.Time) (int64, error) {
+	query := `
+		DELETE FROM users u
+		WHERE u.account_type = $1 AND u.created_at < $2
+			AND NOT EXISTS (SELECT 1 FROM sessions s WHERE s.user_id = u.id AND s.expires_at > $3)`
+
+	result, err := ur.db.Exec(query, models.AccountTypeGuest, createdBefore, time.Now())
+	if err != nil {
+		log.Printf("ERROR: Failed to delete ended guests: %v", err)
+		return 0, fmt.Errorf("error deleting ended guests: %v", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
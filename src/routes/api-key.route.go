@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+)
+
+func APIKeyRoutes(context *models.AppContext) {
+	apiKeyController := controllers.NewAPIKeyController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.Handle("POST /api/api-keys", authMiddleware.RequirePermission(models.PermissionSettingsManage, apiKeyController.CreateAPIKey))
+
+	context.Mux.Handle("GET /api/api-keys", authMiddleware.RequirePermission(models.PermissionSettingsManage, apiKeyController.ListAPIKeys))
+
+	context.Mux.Handle("DELETE /api/api-keys/{id}", authMiddleware.RequirePermission(models.PermissionSettingsManage, apiKeyController.RevokeAPIKey))
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+)
+
+func AuditRoutes(context *models.AppContext) {
+	auditController := controllers.NewAuditController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.Handle("GET /api/audit-log", authMiddleware.RequirePermission(models.PermissionAuditRead, auditController.ListEntries))
+}
@@ -2,13 +2,64 @@ package routes
 
 import (
 	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
 	"restaurant-backend/src/models"
 )
 
 func AuthRoutes(context *models.AppContext) {
 	authController := controllers.NewAuthController(context)
+	passwordController := controllers.NewPasswordController(context)
+	twoFactorController := controllers.NewTwoFactorController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
 
 	context.Mux.HandleFunc("/api/auth/register", authController.RegisterUser)
 
 	context.Mux.HandleFunc("/api/auth/login", authController.LoginUser)
+
+	context.Mux.HandleFunc("/api/auth/login/2fa", authController.LoginTwoFactor)
+
+	context.Mux.HandleFunc("/api/auth/token", authController.Token)
+
+	context.Mux.HandleFunc("/api/auth/token/revoke", authController.RevokeToken)
+
+	context.Mux.HandleFunc("/api/auth/forgot-password", passwordController.ForgotPassword)
+
+	context.Mux.HandleFunc("/api/auth/reset-password", passwordController.ResetPassword)
+
+	context.Mux.HandleFunc("/api/auth/verify-email", authController.VerifyEmail)
+
+	context.Mux.Handle("/api/auth/resend-verification", authMiddleware.RequireAuthFunc(authController.ResendVerification))
+
+	context.Mux.Handle("/api/auth/me", authMiddleware.RequireAuthFunc(authController.Me))
+
+	context.Mux.Handle("/api/auth/permissions", authMiddleware.RequireAuthFunc(authController.Permissions))
+
+	context.Mux.Handle("/api/auth/unlock", authMiddleware.RequirePermission(models.PermissionUsersManage, authController.UnlockAccount))
+
+	context.Mux.Handle("/api/auth/2fa/setup", authMiddleware.RequireAuthFunc(twoFactorController.Setup))
+
+	context.Mux.Handle("/api/auth/2fa/enable", authMiddleware.RequireAuthFunc(twoFactorController.Enable))
+
+	context.Mux.Handle("/api/auth/2fa/disable", authMiddleware.RequireAuthFunc(twoFactorController.Disable))
+
+	context.Mux.Handle("/api/auth/2fa/recovery-codes", authMiddleware.RequireAuthFunc(twoFactorController.RegenerateRecoveryCodes))
+
+	context.Mux.Handle("/api/auth/logout", authMiddleware.RequireAuthFunc(authController.Logout))
+
+	context.Mux.Handle("/api/auth/logout-all", authMiddleware.RequireAuthFunc(authController.LogoutAll))
+
+	context.Mux.Handle("/api/auth/sessions", authMiddleware.RequireAuthFunc(authController.ListSessions))
+
+	context.Mux.Handle("/api/auth/sessions/{id}", authMiddleware.RequireAuthFunc(authController.RevokeSession))
+
+	// Customers and guests have their own flow and never reach the dashboard.
+	context.Mux.HandleFunc("/api/auth/customer/register", authController.RegisterCustomer)
+
+	context.Mux.HandleFunc("/api/auth/customer/login", authController.LoginCustomer)
+
+	context.Mux.HandleFunc("/api/auth/customer/guest", authController.StartGuestSession)
+
+	context.Mux.Handle("/api/auth/customer/me", authMiddleware.RequireCustomer(authController.Me))
+
+	context.Mux.Handle("/api/auth/customer/logout", authMiddleware.RequireCustomer(authController.Logout))
 }
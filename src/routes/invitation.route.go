@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+)
+
+func InvitationRoutes(context *models.AppContext) {
+	invitationController := controllers.NewInvitationController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.Handle("POST /api/invitations", authMiddleware.RequirePermission(models.PermissionUsersManage, invitationController.CreateInvitation))
+
+	context.Mux.Handle("GET /api/invitations", authMiddleware.RequirePermission(models.PermissionUsersManage, invitationController.ListInvitations))
+
+	context.Mux.Handle("DELETE /api/invitations/{id}", authMiddleware.RequirePermission(models.PermissionUsersManage, invitationController.RevokeInvitation))
+
+	context.Mux.HandleFunc("POST /api/invitations/accept", invitationController.AcceptInvitation)
+
+	context.Mux.Handle("POST /api/invitations/join", authMiddleware.RequireAuthFunc(invitationController.JoinInvitation))
+}
@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+)
+
+func MenuRoutes(context *models.AppContext) {
+	menuController := controllers.NewMenuController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	// Public, guests browse the menu without an account.
+	context.Mux.HandleFunc("GET /api/restaurants/{restaurantId}/menu", menuController.GetPublicMenu)
+
+	context.Mux.HandleFunc("GET /api/menu/allergens", menuController.ListAllergens)
+
+	context.Mux.Handle("GET /api/menu", authMiddleware.RequirePermission(models.PermissionMenuRead, menuController.GetMenu))
+
+	context.Mux.Handle("GET /api/menu/categories", authMiddleware.RequirePermission(models.PermissionMenuRead, menuController.ListCategories))
+
+	context.Mux.Handle("POST /api/menu/categories", authMiddleware.RequirePermission(models.PermissionMenuManage, menuController.CreateCategory))
+
+	context.Mux.Handle("PUT /api/menu/categories/order", authMiddleware.RequirePermission(models.PermissionMenuManage, menuController.ReorderCategories))
+
+	context.Mux.Handle("GET /api/menu/categories/{id}", authMiddleware.RequirePermission(models.PermissionMenuRead, menuController.GetCategory))
+
+	context.Mux.Handle("PATCH /api/menu/categories/{id}", authMiddleware.RequirePermission(models.PermissionMenuManage, menuController.UpdateCategory))
+
+	context.Mux.Handle("DELETE /api/menu/categories/{id}", authMiddleware.RequirePermission(models.PermissionMenuManage, menuController.DeleteCategory))
+
+	context.Mux.Handle("PUT /api/menu/categories/{id}/items/order", authMiddleware.RequirePermission(models.PermissionMenuManage, menuController.ReorderItems))
+
+	context.Mux.Handle("GET /api/menu/items", authMiddleware.RequirePermission(models.PermissionMenuRead, menuController.ListItems))
+
+	context.Mux.Handle("POST /api/menu/items", authMiddleware.RequirePermission(models.PermissionMenuManage, menuController.CreateItem))
+
+	context.Mux.Handle("GET /api/menu/items/{id}", authMiddleware.RequirePermission(models.PermissionMenuRead, menuController.GetItem))
+
+	context.Mux.Handle("PATCH /api/menu/items/{id}", authMiddleware.RequirePermission(models.PermissionMenuManage, menuController.UpdateItem))
+
+	context.Mux.Handle("DELETE /api/menu/items/{id}", authMiddleware.RequirePermission(models.PermissionMenuManage, menuController.DeleteItem))
+}
+
+func ModifierRoutes(context *models.AppContext) {
+	modifierController := controllers.NewModifierController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	// Public, so guests can check their choices before ordering.
+	context.Mux.HandleFunc("POST /api/restaurants/{restaurantId}/menu/items/{id}/price", modifierController.PricePublicItem)
+
+	context.Mux.Handle("POST /api/menu/items/{id}/price", authMiddleware.RequirePermission(models.PermissionMenuRead, modifierController.PriceItem))
+
+	context.Mux.Handle("PUT /api/menu/items/{id}/modifier-groups", authMiddleware.RequirePermission(models.PermissionMenuManage, modifierController.SetItemGroups))
+
+	context.Mux.Handle("GET /api/menu/modifier-groups", authMiddleware.RequirePermission(models.PermissionMenuRead, modifierController.ListGroups))
+
+	context.Mux.Handle("POST /api/menu/modifier-groups", authMiddleware.RequirePermission(models.PermissionMenuManage, modifierController.CreateGroup))
+
+	context.Mux.Handle("GET /api/menu/modifier-groups/{id}", authMiddleware.RequirePermission(models.PermissionMenuRead, modifierController.GetGroup))
+
+	context.Mux.Handle("PATCH /api/menu/modifier-groups/{id}", authMiddleware.RequirePermission(models.PermissionMenuManage, modifierController.UpdateGroup))
+
+	context.Mux.Handle("DELETE /api/menu/modifier-groups/{id}", authMiddleware.RequirePermission(models.PermissionMenuManage, modifierController.DeleteGroup))
+
+	context.Mux.Handle("POST /api/menu/modifier-groups/{id}/options", authMiddleware.RequirePermission(models.PermissionMenuManage, modifierController.CreateOption))
+
+	context.Mux.Handle("PATCH /api/menu/modifier-groups/{id}/options/{optionId}", authMiddleware.RequirePermission(models.PermissionMenuManage, modifierController.UpdateOption))
+
+	context.Mux.Handle("DELETE /api/menu/modifier-groups/{id}/options/{optionId}", authMiddleware.RequirePermission(models.PermissionMenuManage, modifierController.DeleteOption))
+}
+
+func ScheduleRoutes(context *models.AppContext) {
+	scheduleController := controllers.NewScheduleController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.Handle("GET /api/menu/orderable", authMiddleware.RequirePermission(models.PermissionMenuRead, scheduleController.GetOrderable))
+
+	context.Mux.Handle("GET /api/menu/menus", authMiddleware.RequirePermission(models.PermissionMenuRead, scheduleController.ListMenus))
+
+	context.Mux.Handle("POST /api/menu/menus", authMiddleware.RequirePermission(models.PermissionMenuManage, scheduleController.CreateMenu))
+
+	context.Mux.Handle("GET /api/menu/menus/{id}", authMiddleware.RequirePermission(models.PermissionMenuRead, scheduleController.GetMenu))
+
+	context.Mux.Handle("PATCH /api/menu/menus/{id}", authMiddleware.RequirePermission(models.PermissionMenuManage, scheduleController.UpdateMenu))
+
+	context.Mux.Handle("DELETE /api/menu/menus/{id}", authMiddleware.RequirePermission(models.PermissionMenuManage, scheduleController.DeleteMenu))
+
+	context.Mux.Handle("PUT /api/menu/menus/{id}/categories", authMiddleware.RequirePermission(models.PermissionMenuManage, scheduleController.SetCategories))
+
+	context.Mux.Handle("PUT /api/menu/menus/{id}/schedule", authMiddleware.RequirePermission(models.PermissionMenuManage, scheduleController.SetSchedule))
+}
+
+func TranslationRoutes(context *models.AppContext) {
+	translationController := controllers.NewTranslationController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.Handle("GET /api/menu/translations", authMiddleware.RequirePermission(models.PermissionMenuRead, translationController.ListTranslations))
+
+	context.Mux.Handle("GET /api/menu/translations/missing", authMiddleware.RequirePermission(models.PermissionMenuRead, translationController.ListMissing))
+
+	context.Mux.Handle("PUT /api/menu/translations/{entityType}/{entityId}/{field}/{locale}", authMiddleware.RequirePermission(models.PermissionMenuManage, translationController.SetTranslation))
+
+	context.Mux.Handle("DELETE /api/menu/translations/{entityType}/{entityId}/{field}/{locale}", authMiddleware.RequirePermission(models.PermissionMenuManage, translationController.DeleteTranslation))
+}
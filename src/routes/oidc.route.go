@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+)
+
+func OIDCRoutes(context *models.AppContext) {
+	oidcController := controllers.NewOIDCController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.HandleFunc("GET /api/auth/oidc/providers", oidcController.ListProviders)
+
+	context.Mux.HandleFunc("GET /api/auth/oidc/{provider}/login", oidcController.Login)
+
+	context.Mux.HandleFunc("GET /api/auth/oidc/{provider}/callback", oidcController.Callback)
+
+	context.Mux.Handle("GET /api/users/me/identities", authMiddleware.RequireAuthFunc(oidcController.ListIdentities))
+
+	context.Mux.Handle("GET /api/users/me/identities/{provider}/link", authMiddleware.RequireAuthFunc(oidcController.LinkIdentity))
+
+	context.Mux.Handle("DELETE /api/users/me/identities/{id}", authMiddleware.RequireAuthFunc(oidcController.UnlinkIdentity))
+}
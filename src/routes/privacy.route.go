@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+)
+
+func PrivacyRoutes(context *models.AppContext) {
+	privacyController := controllers.NewPrivacyController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.Handle("GET /api/users/me/export", authMiddleware.RequireAuthFunc(privacyController.Export))
+
+	context.Mux.Handle("POST /api/users/me/erasure-request", authMiddleware.RequireAuthFunc(privacyController.RequestErasure))
+
+	context.Mux.Handle("GET /api/auth/customer/me/export", authMiddleware.RequireCustomer(privacyController.Export))
+
+	context.Mux.Handle("POST /api/auth/customer/me/erasure-request", authMiddleware.RequireCustomer(privacyController.RequestErasure))
+
+	context.Mux.Handle("GET /api/privacy/requests", authMiddleware.RequirePermission(models.PermissionPrivacyManage, privacyController.ListRequests))
+
+	context.Mux.Handle("POST /api/privacy/requests/{id}/complete", authMiddleware.RequirePermission(models.PermissionPrivacyManage, privacyController.CompleteRequest))
+
+	context.Mux.Handle("POST /api/privacy/requests/{id}/reject", authMiddleware.RequirePermission(models.PermissionPrivacyManage, privacyController.RejectRequest))
+}
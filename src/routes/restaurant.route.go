@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+)
+
+func RestaurantRoutes(context *models.AppContext) {
+	restaurantController := controllers.NewRestaurantController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.Handle("GET /api/restaurants", authMiddleware.RequireAuthFunc(restaurantController.ListRestaurants))
+
+	context.Mux.Handle("POST /api/restaurants", authMiddleware.RequirePermission(models.PermissionSettingsManage, restaurantController.CreateRestaurant))
+
+	context.Mux.Handle("POST /api/organizations", authMiddleware.RequirePermission(models.PermissionSettingsManage, restaurantController.CreateOrganization))
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"restaurant-backend/src/controllers"
+	"restaurant-backend/src/middlewares"
+	"restaurant-backend/src/models"
+)
+
+func UserRoutes(context *models.AppContext) {
+	userController := controllers.NewUserController(context)
+	authMiddleware := middlewares.NewAuthMiddleware(context)
+
+	context.Mux.Handle("GET /api/users/me", authMiddleware.RequireAuthFunc(userController.GetProfile))
+
+	context.Mux.Handle("PATCH /api/users/me", authMiddleware.RequireAuthFunc(userController.UpdateProfile))
+
+	context.Mux.Handle("POST /api/users/me/password", authMiddleware.RequireAuthFunc(userController.ChangePassword))
+
+	context.Mux.Handle("POST /api/users/me/email", authMiddleware.RequireAuthFunc(userController.ChangeEmail))
+
+	// Staff administration works on the active restaurant, chosen with the
+	// X-Restaurant-Id header or the path segment of the second prefix.
+	for _, prefix := range []string{"/api/users", "/api/restaurants/{restaurantId}/users"} {
+		context.Mux.Handle("GET "+prefix, authMiddleware.RequirePermission(models.PermissionUsersRead, userController.ListUsers))
+
+		context.Mux.Handle("POST "+prefix, authMiddleware.RequirePermission(models.PermissionUsersManage, userController.AddMember))
+
+		context.Mux.Handle("GET "+prefix+"/{id}", authMiddleware.RequirePermission(models.PermissionUsersRead, userController.GetUser))
+
+		context.Mux.Handle("PATCH "+prefix+"/{id}/role", authMiddleware.RequirePermission(models.PermissionRolesManage, userController.UpdateUserRole))
+
+		context.Mux.Handle("POST "+prefix+"/{id}/deactivate", authMiddleware.RequirePermission(models.PermissionUsersManage, userController.DeactivateUser))
+
+		context.Mux.Handle("POST "+prefix+"/{id}/reactivate", authMiddleware.RequirePermission(models.PermissionUsersManage, userController.ReactivateUser))
+
+		context.Mux.Handle("DELETE "+prefix+"/{id}", authMiddleware.RequirePermission(models.PermissionUsersManage, userController.DeleteUser))
+	}
+}
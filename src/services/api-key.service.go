@@ -0,0 +1,82 @@
+package services
+
+import (
+	"log"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyPrefix = "rk_"
+	// Same throttling as session touches, one write per key per minute.
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyService struct {
+	apiKeyRepo *repositories.APIKeyRepository
+}
+
+func NewAPIKeyService(ctx *models.AppContext) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: repositories.NewAPIKeyRepository(ctx.DB),
+	}
+}
+
+// Create stores a new key and returns its plaintext, which is not kept
+// anywhere and cannot be shown again. The key only works for the
+// restaurant it is created in.
+func (as *APIKeyService) Create(creator *models.User, restaurantId uuid.UUID, name string, scopes []string, expiresAt *time.Time) (string, *models.APIKey, error) {
+	plain := apiKeyPrefix + utils.GenerateRandomToken()
+
+	key := &models.APIKey{
+		RestaurantId: restaurantId,
+		Name:         name,
+		Prefix:       plain[:len(apiKeyPrefix)+8],
+		KeyHash:      utils.HashString(plain),
+		Scopes:       scopes,
+		CreatedBy:    &creator.Id,
+		ExpiresAt:    expiresAt,
+	}
+
+	if err := as.apiKeyRepo.CreateAPIKey(key); err != nil {
+		return "", nil, err
+	}
+
+	return plain, key, nil
+}
+
+// Resolve returns the active key for a plaintext value, or nil.
+func (as *APIKeyService) Resolve(plain string) (*models.APIKey, error) {
+	plain = strings.TrimSpace(plain)
+	if !strings.HasPrefix(plain, apiKeyPrefix) {
+		return nil, nil
+	}
+
+	key, err := as.apiKeyRepo.GetActiveAPIKeyByHash(utils.HashString(plain))
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := as.apiKeyRepo.TouchAPIKey(key.Id, now); err != nil {
+			log.Printf("ERROR: Failed to record API key usage: %v", err)
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
+
+func (as *APIKeyService) List(restaurantId uuid.UUID) ([]*models.APIKey, error) {
+	return as.apiKeyRepo.GetAPIKeys(restaurantId)
+}
+
+func (as *APIKeyService) Revoke(restaurantId, id uuid.UUID) (bool, error) {
+	return as.apiKeyRepo.RevokeAPIKey(restaurantId, id)
+}
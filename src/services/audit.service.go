@@ -0,0 +1,69 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/utils"
+)
+
+// AuditService records who did what. Recording never fails the request
+// it belongs to, errors are only logged.
+type AuditService struct {
+	auditRepo *repositories.AuditRepository
+}
+
+func NewAuditService(ctx *models.AppContext) *AuditService {
+	return &AuditService{
+		auditRepo: repositories.NewAuditRepository(ctx.DB),
+	}
+}
+
+// Record stores an event. actor may be nil for anonymous requests and
+// changes is usually a models.AuditDiff, or a map of details for events
+// that do not change a record. The entry belongs to the active restaurant
+// of the request, if there is one, and names the API key the request was
+// made with.
+func (as *AuditService) Record(r *http.Request, actor *models.User, action, targetType, targetId string, changes any) {
+	entry := &models.AuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		IPAddress:  utils.Truncate(utils.GetClientIP(r), 64),
+		UserAgent:  utils.Truncate(r.UserAgent(), 512),
+		Changes:    json.RawMessage("{}"),
+	}
+
+	if restaurant := models.RestaurantFromContext(r.Context()); restaurant != nil {
+		entry.RestaurantId = &restaurant.Id
+	}
+
+	if actor != nil {
+		entry.ActorId = &actor.Id
+		entry.ActorEmail = actor.Email
+	}
+
+	if key := models.APIKeyFromContext(r.Context()); key != nil {
+		entry.APIKeyId = &key.Id
+		entry.APIKeyPrefix = key.Prefix
+	}
+
+	if changes != nil {
+		encoded, err := json.Marshal(changes)
+		if err != nil {
+			log.Printf("ERROR: Failed to encode audit changes for %s: %v", action, err)
+		} else {
+			entry.Changes = encoded
+		}
+	}
+
+	if err := as.auditRepo.CreateEntry(entry); err != nil {
+		log.Printf("ERROR: Failed to record audit event %s: %v", action, err)
+	}
+}
+
+func (as *AuditService) List(filter models.AuditLogFilter, limit, offset int) ([]*models.AuditEntry, int, error) {
+	return as.auditRepo.ListEntries(filter, limit, offset)
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/utils"
+	"time"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user with this email not exists")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrAccountDisabled    = errors.New("account is deactivated")
+)
+
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry after %s", e.RetryAfter)
+}
+
+// DefaultUserRole is the role of self registered and OIDC signed up
+// accounts. Anyone can register, so roles that manage the restaurant are
+// never handed out this way.
+func DefaultUserRole(appConfig *config.AppConfig) (models.Role, error) {
+	if !models.IsValidRole(appConfig.DefaultUserRole) {
+		return "", fmt.Errorf("unknown role %q", appConfig.DefaultUserRole)
+	}
+
+	role := models.Role(appConfig.DefaultUserRole)
+	if role == models.RoleOwner || role == models.RoleManager {
+		return "", fmt.Errorf("role %q cannot be given to self registered accounts", role)
+	}
+
+	return role, nil
+}
+
+// AuthService checks email and password credentials for every login
+// flow, cookie based or token based, and applies login throttling. Staff
+// and customers sign in through separate methods, neither accepts the
+// other kind of account.
+type AuthService struct {
+	userRepo        *repositories.UserRepository
+	throttleService *LoginThrottleService
+}
+
+func NewAuthService(ctx *models.AppContext) *AuthService {
+	return &AuthService{
+		userRepo:        repositories.NewUserRepository(ctx.DB),
+		throttleService: NewLoginThrottleService(ctx),
+	}
+}
+
+// Authenticate signs in staff by email.
+func (as *AuthService) Authenticate(email, password, ip string) (*models.User, error) {
+	return as.authenticate(email, password, ip, func() (*models.User, error) {
+		user, err := as.userRepo.GetUserByEmail(email)
+		if err != nil || user == nil || !user.IsStaff() {
+			return nil, err
+		}
+		return user, nil
+	})
+}
+
+// AuthenticateCustomer signs in customers by email or normalized phone
+// number.
+func (as *AuthService) AuthenticateCustomer(login, password, ip string) (*models.User, error) {
+	return as.authenticate(login, password, ip, func() (*models.User, error) {
+		return as.userRepo.GetCustomerByLogin(login)
+	})
+}
+
+// authenticate returns the account together with ErrInvalidCredentials and
+// ErrAccountDisabled, so the failure can be recorded against it. Callers
+// must not sign it in then.
+func (as *AuthService) authenticate(login, password, ip string, lookup func() (*models.User, error)) (*models.User, error) {
+	retryAfter, err := as.throttleService.BeginAttempt(login, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	if retryAfter > 0 {
+		return nil, &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	user, err := lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		as.registerFailure(login, ip)
+		return nil, ErrUserNotFound
+	}
+
+	if !utils.IsPasswordEqualHash(user.Password, password) {
+		as.registerFailure(login, ip)
+		return user, ErrInvalidCredentials
+	}
+
+	if err := as.throttleService.RegisterSuccess(login, ip); err != nil {
+		log.Printf("ERROR: Failed to reset login throttle: %v", err)
+	}
+
+	if !user.IsActive {
+		return user, ErrAccountDisabled
+	}
+
+	as.rehashIfNeeded(user, password)
+
+	return user, nil
+}
+
+// rehashIfNeeded upgrades a stored hash to the current algorithm and cost
+// while the plain password is at hand. Failures only delay the upgrade.
+func (as *AuthService) rehashIfNeeded(user *models.User, password string) {
+	if !utils.PasswordNeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		log.Printf("ERROR: Failed to rehash password: %v", err)
+		return
+	}
+
+	if err := as.userRepo.UpdatePassword(user.Id, hashedPassword); err != nil {
+		log.Printf("ERROR: Failed to store rehashed password: %v", err)
+		return
+	}
+
+	user.Password = hashedPassword
+}
+
+func (as *AuthService) registerFailure(login, ip string) {
+	if err := as.throttleService.RegisterFailure(login, ip); err != nil {
+		log.Printf("ERROR: Failed to register login failure: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/mailer"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/utils"
+	"strings"
+	"time"
+)
+
+type EmailVerificationService struct {
+	userRepo         *repositories.UserRepository
+	verificationRepo *repositories.EmailVerificationRepository
+	mailer           mailer.Mailer
+	config           *config.AppConfig
+}
+
+func NewEmailVerificationService(ctx *models.AppContext) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:         repositories.NewUserRepository(ctx.DB),
+		verificationRepo: repositories.NewEmailVerificationRepository(ctx.DB),
+		mailer:           mailer.NewMailer(ctx.Config.Mail),
+		config:           ctx.Config.App,
+	}
+}
+
+// SendVerification issues a one-time token for the given address and
+// mails the verification link to it.
+func (vs *EmailVerificationService) SendVerification(user *models.User, email string) error {
+	token := utils.GenerateRandomToken()
+	ttl := time.Duration(vs.config.EmailVerificationTTLHours) * time.Hour
+
+	if err := vs.verificationRepo.CreateToken(user.Id, email, utils.HashString(token), time.Now().Add(ttl)); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", strings.TrimRight(vs.config.FrontendURL, "/"), token)
+
+	return vs.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by opening the link below. It expires in %d hours.\n\n%s",
+			user.Name, vs.config.EmailVerificationTTLHours, link),
+	})
+}
+
+var ErrEmailTaken = errors.New("user with this email already exists")
+
+// Verify consumes the token and marks the address as verified. A token
+// issued for a different address than the current one completes an email
+// change. It reports false when the token is invalid or no longer matches
+// the account.
+func (vs *EmailVerificationService) Verify(token string) (bool, error) {
+	userId, email, err := vs.verificationRepo.ConsumeToken(utils.HashString(token))
+	if err != nil || email == "" {
+		return false, err
+	}
+
+	user, err := vs.userRepo.GetUserById(userId.String())
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	if user.Email == email {
+		return vs.userRepo.MarkEmailVerified(userId, email)
+	}
+
+	exists, err := vs.userRepo.UserExists(email)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, ErrEmailTaken
+	}
+
+	if err := vs.userRepo.UpdateEmail(userId, email); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RequestEmailChange sends a verification link to the new address. The
+// account keeps its current email until that link is used.
+func (vs *EmailVerificationService) RequestEmailChange(user *models.User, newEmail string) error {
+	if err := vs.verificationRepo.InvalidateUserTokens(user.Id); err != nil {
+		return err
+	}
+
+	if err := vs.SendVerification(user, newEmail); err != nil {
+		return err
+	}
+
+	return vs.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Email change requested",
+		Body: fmt.Sprintf("Hello %s,\n\nA change of your account email to %s was requested. If this was not you, please reset your password.",
+			user.Name, newEmail),
+	})
+}
+
+// IsBlocked reports whether unverified accounts are kept away from the
+// given permission.
+func (vs *EmailVerificationService) IsBlocked(user *models.User, permission string) bool {
+	if user.IsEmailVerified() {
+		return false
+	}
+
+	for _, feature := range vs.config.UnverifiedBlockedFeatures {
+		if feature == "*" || feature == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"log"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"time"
+)
+
+type LoginThrottleService struct {
+	throttleRepo *repositories.LoginThrottleRepository
+	config       *config.AppConfig
+}
+
+func NewLoginThrottleService(ctx *models.AppContext) *LoginThrottleService {
+	return &LoginThrottleService{
+		throttleRepo: repositories.NewLoginThrottleRepository(ctx.DB),
+		config:       ctx.Config.App,
+	}
+}
+
+func (ts *LoginThrottleService) lockoutDuration() time.Duration {
+	return time.Duration(ts.config.LoginLockoutMinutes) * time.Minute
+}
+
+func (ts *LoginThrottleService) guestWindow() time.Duration {
+	return time.Duration(ts.config.GuestSessionWindowMinutes) * time.Minute
+}
+
+// backoff doubles the required wait with every failure in a row.
+func (ts *LoginThrottleService) backoff(failedCount int) time.Duration {
+	if failedCount <= 0 {
+		return 0
+	}
+
+	delay := time.Duration(ts.config.LoginBackoffBaseSeconds) * time.Second
+	limit := time.Duration(ts.config.LoginBackoffMaxSeconds) * time.Second
+
+	for i := 1; i < failedCount && delay < limit; i++ {
+		delay *= 2
+	}
+
+	if delay > limit {
+		return limit
+	}
+
+	return delay
+}
+
+func (ts *LoginThrottleService) keys(email, ip string) []models.ThrottleKey {
+	return []models.ThrottleKey{
+		{Scope: models.ThrottleScopeEmail, Key: email},
+		{Scope: models.ThrottleScopeIP, Key: ip},
+	}
+}
+
+// BeginAttempt counts a login attempt for this email and IP before the
+// credentials are checked, and returns how long the caller has to wait
+// instead when the attempt is not accepted. Zero means go ahead. The
+// attempt counts as failed until RegisterSuccess takes it back.
+func (ts *LoginThrottleService) BeginAttempt(email, ip string) (time.Duration, error) {
+	windowStart := time.Now().Add(-ts.lockoutDuration())
+	base := time.Duration(ts.config.LoginBackoffBaseSeconds) * time.Second
+	limit := time.Duration(ts.config.LoginBackoffMaxSeconds) * time.Second
+
+	accepted, err := ts.throttleRepo.BeginAttempt(ts.keys(email, ip), windowStart, base, limit)
+	if err != nil || accepted {
+		return 0, err
+	}
+
+	wait, err := ts.retryAfter(email, ip)
+	if err != nil {
+		return 0, err
+	}
+
+	// The wait may have run out in between, the attempt was refused all
+	// the same.
+	return max(wait, time.Second), nil
+}
+
+// retryAfter returns how long until another attempt for this email and IP
+// is accepted.
+func (ts *LoginThrottleService) retryAfter(email, ip string) (time.Duration, error) {
+	var wait time.Duration
+
+	for _, key := range ts.keys(email, ip) {
+		throttle, err := ts.throttleRepo.GetThrottle(key.Scope, key.Key)
+		if err != nil {
+			return 0, err
+		}
+		if throttle == nil {
+			continue
+		}
+
+		now := time.Now()
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			wait = max(wait, throttle.LockedUntil.Sub(now))
+			continue
+		}
+
+		if now.Sub(throttle.LastFailedAt) > ts.lockoutDuration() {
+			continue
+		}
+
+		if next := throttle.LastFailedAt.Add(ts.backoff(throttle.FailedCount)); next.After(now) {
+			wait = max(wait, next.Sub(now))
+		}
+	}
+
+	return wait, nil
+}
+
+// RegisterFailure locks the email or the IP once their threshold is
+// reached. The attempt itself was counted by BeginAttempt.
+func (ts *LoginThrottleService) RegisterFailure(email, ip string) error {
+	limits := []struct {
+		scope string
+		key   string
+		max   int
+	}{
+		{models.ThrottleScopeEmail, email, ts.config.LoginMaxAttempts},
+		{models.ThrottleScopeIP, ip, ts.config.LoginIPMaxAttempts},
+	}
+
+	for _, limit := range limits {
+		if limit.max <= 0 {
+			continue
+		}
+
+		until := time.Now().Add(ts.lockoutDuration())
+		count, locked, err := ts.throttleRepo.LockIfReached(limit.scope, limit.key, limit.max, until)
+		if err != nil {
+			return err
+		}
+
+		if locked {
+			log.Printf("SECURITY: Login locked for %s %q after %d failed attempts (ip=%s) until %s",
+				limit.scope, limit.key, count, ip, until.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// RegisterSuccess clears the email counter and takes the attempt back from
+// the IP counter. The rest of the IP counter is left alone so that one
+// valid account cannot be used to reset guessing from an IP.
+func (ts *LoginThrottleService) RegisterSuccess(email, ip string) error {
+	if _, err := ts.throttleRepo.Reset(models.ThrottleScopeEmail, email); err != nil {
+		return err
+	}
+
+	return ts.throttleRepo.ReleaseAttempt(models.ThrottleScopeIP, ip)
+}
+
+// BeginGuestSession counts a guest session started from the IP, and
+// returns how long the caller has to wait instead once the IP has started
+// GUEST_SESSION_MAX_PER_IP of them within the window. Zero means go ahead.
+func (ts *LoginThrottleService) BeginGuestSession(ip string) (time.Duration, error) {
+	if ts.config.GuestSessionMaxPerIP <= 0 {
+		return 0, nil
+	}
+
+	accepted, err := ts.throttleRepo.CountWithin(models.ThrottleScopeGuest, ip, time.Now().Add(-ts.guestWindow()), ts.config.GuestSessionMaxPerIP)
+	if err != nil || accepted {
+		return 0, err
+	}
+
+	throttle, err := ts.throttleRepo.GetThrottle(models.ThrottleScopeGuest, ip)
+	if err != nil {
+		return 0, err
+	}
+
+	wait := time.Second
+	if throttle != nil {
+		wait = max(wait, time.Until(throttle.LastFailedAt.Add(ts.guestWindow())))
+	}
+
+	return wait, nil
+}
+
+// Unlock resets the counters of the email and, unless it is empty, of the
+// IP address. It reports whether either was throttled.
+func (ts *LoginThrottleService) Unlock(email, ip string) (bool, error) {
+	unlocked, err := ts.throttleRepo.Reset(models.ThrottleScopeEmail, email)
+	if err != nil || ip == "" {
+		return unlocked, err
+	}
+
+	ipUnlocked, err := ts.throttleRepo.Reset(models.ThrottleScopeIP, ip)
+	if err != nil {
+		return false, err
+	}
+
+	return unlocked || ipUnlocked, nil
+}
+
+// StartCleanup periodically removes counters that have run out, in the
+// background, on the session cleanup interval.
+func (ts *LoginThrottleService) StartCleanup() {
+	interval := time.Duration(ts.config.SessionCleanupIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			deleted, err := ts.throttleRepo.DeleteStale(time.Now().Add(-max(ts.lockoutDuration(), ts.guestWindow())))
+			if err != nil {
+				log.Printf("ERROR: Login throttle cleanup failed: %v", err)
+				continue
+			}
+
+			if deleted > 0 {
+				log.Printf("Removed %d stale login throttles", deleted)
+			}
+		}
+	}()
+}
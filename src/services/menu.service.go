@@ -0,0 +1,493 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxOrderQuantity caps the quantity of one configured item.
+const MaxOrderQuantity = 100
+
+type MenuService struct {
+	menuRepo       *repositories.MenuRepository
+	modifierRepo   *repositories.ModifierRepository
+	scheduleRepo   *repositories.ScheduleRepository
+	restaurantRepo *repositories.RestaurantRepository
+}
+
+func NewMenuService(ctx *models.AppContext) *MenuService {
+	return &MenuService{
+		menuRepo:       repositories.NewMenuRepository(ctx.DB),
+		modifierRepo:   repositories.NewModifierRepository(ctx.DB),
+		scheduleRepo:   repositories.NewScheduleRepository(ctx.DB),
+		restaurantRepo: repositories.NewRestaurantRepository(ctx.DB),
+	}
+}
+
+// GetMenu returns the categories in menu order with their matching items
+// and modifier groups, see attachModifierGroups. When the filter asks for
+// available items only, unavailable categories are left out too, which is
+// what guests get to see.
+func (ms *MenuService) GetMenu(filter models.MenuItemFilter) ([]*models.MenuCategory, error) {
+	onlyAvailable := filter.IsAvailable != nil && *filter.IsAvailable
+
+	categories, err := ms.menuRepo.GetCategories(filter.RestaurantId, onlyAvailable)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := ms.menuRepo.GetItems(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if items, err = ms.attachModifierGroups(filter, items); err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uuid.UUID]*models.MenuCategory, len(categories))
+	for _, category := range categories {
+		category.Items = []*models.MenuItem{}
+		byId[category.Id] = category
+	}
+
+	for _, item := range items {
+		if category, ok := byId[item.CategoryId]; ok {
+			category.Items = append(category.Items, item)
+		}
+	}
+
+	return categories, nil
+}
+
+// GetOrderableMenu is GetMenu limited to what can be ordered at the given
+// time: available categories in an open menu or in no menu at all. It also
+// returns the open menus.
+func (ms *MenuService) GetOrderableMenu(restaurant *models.Restaurant, filter models.MenuItemFilter, at time.Time) ([]*models.Menu, []*models.MenuCategory, error) {
+	available := true
+	filter.RestaurantId = restaurant.Id
+	filter.IsAvailable = &available
+
+	categories, err := ms.GetMenu(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	menus, err := ms.scheduleRepo.GetMenus(restaurant.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	at = at.In(RestaurantLocation(restaurant))
+	open := OpenMenus(menus, at)
+
+	orderable := []*models.MenuCategory{}
+	for _, category := range categories {
+		if IsCategoryOrderable(menus, open, category.Id) {
+			orderable = append(orderable, category)
+		}
+	}
+
+	return open, orderable, nil
+}
+
+// OrderingViolations reports the item when its category is unavailable, or
+// when none of the menus of its category is open at the given time. The
+// item's own availability is checked by ValidateConfiguration.
+func (ms *MenuService) OrderingViolations(restaurantId uuid.UUID, item *models.MenuItem, at time.Time) ([]models.ConfigurationViolation, error) {
+	violations := []models.ConfigurationViolation{}
+
+	category, err := ms.menuRepo.GetCategory(restaurantId, item.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.IsAvailable && (category == nil || !category.IsAvailable) {
+		violations = append(violations, models.ConfigurationViolation{
+			Rule:    models.ConfigurationRuleItemUnavailable,
+			Message: fmt.Sprintf("%s is not available", item.Name),
+		})
+	}
+
+	restaurant, err := ms.restaurantRepo.GetRestaurant(restaurantId)
+	if err != nil || restaurant == nil {
+		return violations, err
+	}
+
+	menus, err := ms.scheduleRepo.GetMenus(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	open := OpenMenus(menus, at.In(RestaurantLocation(restaurant)))
+	if !IsCategoryOrderable(menus, open, item.CategoryId) {
+		violations = append(violations, models.ConfigurationViolation{
+			Rule:    models.ConfigurationRuleOutsideMenuHours,
+			Message: fmt.Sprintf("%s cannot be ordered at this time", item.Name),
+		})
+	}
+
+	return violations, nil
+}
+
+// RestaurantLocation returns the restaurant's time zone, or UTC when it
+// cannot be loaded.
+func RestaurantLocation(restaurant *models.Restaurant) *time.Location {
+	location, err := time.LoadLocation(restaurant.Timezone)
+	if err != nil {
+		log.Printf("WARNING: Unknown timezone %q of restaurant %s: %v", restaurant.Timezone, restaurant.Id, err)
+		return time.UTC
+	}
+
+	return location
+}
+
+// OpenMenus returns the menus open at the given time, which must be in the
+// restaurant's time zone.
+func OpenMenus(menus []*models.Menu, at time.Time) []*models.Menu {
+	open := []*models.Menu{}
+
+	for _, menu := range menus {
+		if IsMenuOpen(menu, at) {
+			open = append(open, menu)
+		}
+	}
+
+	return open
+}
+
+// IsCategoryOrderable reports whether the category is in one of the open
+// menus. A category that is in no menu at all is always orderable.
+func IsCategoryOrderable(menus, open []*models.Menu, categoryId uuid.UUID) bool {
+	for _, menu := range open {
+		if slices.Contains(menu.CategoryIds, categoryId) {
+			return true
+		}
+	}
+
+	for _, menu := range menus {
+		if slices.Contains(menu.CategoryIds, categoryId) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsMenuOpen checks the windows of the day of at, and the windows of the
+// day before that run past midnight. Overrides replace the windows of the
+// days they cover.
+func IsMenuOpen(menu *models.Menu, at time.Time) bool {
+	if !menu.IsAvailable {
+		return false
+	}
+
+	now := models.ClockTimeOf(at)
+
+	for _, window := range windowsOn(menu, at) {
+		if window.Start < window.End && window.Start <= now && now < window.End {
+			return true
+		}
+		if window.End < window.Start && now >= window.Start {
+			return true
+		}
+	}
+
+	for _, window := range windowsOn(menu, at.AddDate(0, 0, -1)) {
+		if window.End < window.Start && now < window.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+func windowsOn(menu *models.Menu, day time.Time) []*models.MenuWindow {
+	date := day.Format(time.DateOnly)
+
+	for _, override := range menu.Overrides {
+		if date < override.StartDate || date > override.EndDate {
+			continue
+		}
+		if !override.IsAvailable {
+			return nil
+		}
+		if override.Start == nil || override.End == nil {
+			return []*models.MenuWindow{{Weekday: int(day.Weekday()), Start: 0, End: models.MinutesPerDay}}
+		}
+		return []*models.MenuWindow{{Weekday: int(day.Weekday()), Start: *override.Start, End: *override.End}}
+	}
+
+	windows := []*models.MenuWindow{}
+	for _, window := range menu.Windows {
+		if window.Weekday == int(day.Weekday()) {
+			windows = append(windows, window)
+		}
+	}
+
+	return windows
+}
+
+// GetItems returns the matching items without their modifier groups. The
+// options still count towards the allergen filter, see
+// attachModifierGroups.
+func (ms *MenuService) GetItems(filter models.MenuItemFilter) ([]*models.MenuItem, error) {
+	items, err := ms.menuRepo.GetItems(filter)
+	if err != nil || len(filter.ExcludeAllergens) == 0 {
+		return items, err
+	}
+
+	if items, err = ms.attachModifierGroups(filter, items); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		item.ModifierGroups = nil
+	}
+
+	return items, nil
+}
+
+// GetItem returns the item with its modifier groups, or nil.
+func (ms *MenuService) GetItem(restaurantId, id uuid.UUID) (*models.MenuItem, error) {
+	item, err := ms.menuRepo.GetItem(restaurantId, id)
+	if err != nil || item == nil {
+		return nil, err
+	}
+
+	if item.ModifierGroups, err = ms.modifierRepo.GetItemGroups(item.Id); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// attachModifierGroups adds the groups of each item. Unavailable options
+// are left out when the filter asks for available items only, and options
+// that may contain an excluded allergen when it excludes any. Items are
+// then dropped when one of their groups no longer has enough options to
+// make a valid choice.
+func (ms *MenuService) attachModifierGroups(filter models.MenuItemFilter, items []*models.MenuItem) ([]*models.MenuItem, error) {
+	groups, err := ms.modifierRepo.GetGroups(filter.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := ms.modifierRepo.GetGroupLinks(filter.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	onlyAvailable := filter.IsAvailable != nil && *filter.IsAvailable
+
+	groupsById := make(map[uuid.UUID]*models.ModifierGroup, len(groups))
+	for _, group := range groups {
+		if onlyAvailable || len(filter.ExcludeAllergens) > 0 {
+			group = withOptions(group, func(option *models.ModifierOption) bool {
+				return (!onlyAvailable || option.IsAvailable) && isFreeOf(option, filter.ExcludeAllergens)
+			})
+		}
+		groupsById[group.Id] = group
+	}
+
+	itemsById := make(map[uuid.UUID]*models.MenuItem, len(items))
+	for _, item := range items {
+		itemsById[item.Id] = item
+	}
+
+	for _, link := range links {
+		item, ok := itemsById[link.ItemId]
+		if !ok {
+			continue
+		}
+		item.ModifierGroups = append(item.ModifierGroups, groupsById[link.GroupId])
+	}
+
+	if len(filter.ExcludeAllergens) == 0 {
+		return items, nil
+	}
+
+	kept := make([]*models.MenuItem, 0, len(items))
+	for _, item := range items {
+		if slices.IndexFunc(item.ModifierGroups, func(group *models.ModifierGroup) bool {
+			return len(group.Options) < group.MinimumSelections()
+		}) < 0 {
+			kept = append(kept, item)
+		}
+	}
+
+	return kept, nil
+}
+
+// withOptions returns a copy of the group with only the options keep
+// accepts. Groups are shared between items, so they are never changed in
+// place.
+func withOptions(group *models.ModifierGroup, keep func(*models.ModifierOption) bool) *models.ModifierGroup {
+	filtered := *group
+	filtered.Options = []*models.ModifierOption{}
+
+	for _, option := range group.Options {
+		if keep(option) {
+			filtered.Options = append(filtered.Options, option)
+		}
+	}
+
+	return &filtered
+}
+
+// isFreeOf reports whether the option declares none of the allergens. An
+// option that has not declared its allergens may contain any of them.
+func isFreeOf(option *models.ModifierOption, allergens []string) bool {
+	if len(allergens) == 0 {
+		return true
+	}
+	if !option.AllergensDeclared {
+		return false
+	}
+
+	return !slices.ContainsFunc(option.Allergens, func(allergen string) bool {
+		return slices.Contains(allergens, allergen)
+	})
+}
+
+// PriceItem checks the chosen options against the item, see
+// ValidateConfiguration, and that it can be ordered at the given time, see
+// OrderingViolations. The item must come with its modifier groups, as
+// GetItem returns it.
+func (ms *MenuService) PriceItem(restaurantId uuid.UUID, item *models.MenuItem, optionIds []uuid.UUID, quantity int, at time.Time) (*models.PricedItem, []models.ConfigurationViolation, error) {
+	violations, err := ms.OrderingViolations(restaurantId, item, at)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	priced, configurationViolations := ValidateConfiguration(item, optionIds, quantity)
+	if violations = append(violations, configurationViolations...); len(violations) > 0 {
+		return nil, violations, nil
+	}
+
+	return priced, nil, nil
+}
+
+// ValidateConfiguration checks chosen options against the item's modifier
+// groups and works out the price. Every broken rule is reported, and the
+// priced item is only returned when there are none. The item must come
+// with its modifier groups loaded.
+func ValidateConfiguration(item *models.MenuItem, optionIds []uuid.UUID, quantity int) (*models.PricedItem, []models.ConfigurationViolation) {
+	violations := []models.ConfigurationViolation{}
+
+	if !item.IsAvailable {
+		violations = append(violations, models.ConfigurationViolation{
+			Rule:    models.ConfigurationRuleItemUnavailable,
+			Message: fmt.Sprintf("%s is not available", item.Name),
+		})
+	}
+
+	if quantity < 1 || quantity > MaxOrderQuantity {
+		violations = append(violations, models.ConfigurationViolation{
+			Rule:    models.ConfigurationRuleQuantity,
+			Message: fmt.Sprintf("quantity must be between 1 and %d", MaxOrderQuantity),
+		})
+	}
+
+	type choice struct {
+		group  *models.ModifierGroup
+		option *models.ModifierOption
+	}
+
+	offered := map[uuid.UUID]choice{}
+	for _, group := range item.ModifierGroups {
+		for _, option := range group.Options {
+			offered[option.Id] = choice{group, option}
+		}
+	}
+
+	chosen := []*models.ModifierOption{}
+	counts := map[uuid.UUID]int{}
+	seen := map[uuid.UUID]bool{}
+
+	for _, optionId := range optionIds {
+		found, ok := offered[optionId]
+		if !ok {
+			violations = append(violations, models.ConfigurationViolation{
+				Rule:     models.ConfigurationRuleUnknownOption,
+				OptionId: &optionId,
+				Message:  "option is not offered for this item",
+			})
+			continue
+		}
+
+		if seen[optionId] {
+			violations = append(violations, models.ConfigurationViolation{
+				Rule:     models.ConfigurationRuleDuplicateOption,
+				GroupId:  &found.group.Id,
+				OptionId: &optionId,
+				Message:  fmt.Sprintf("%s is chosen more than once", found.option.Name),
+			})
+			continue
+		}
+		seen[optionId] = true
+
+		if !found.option.IsAvailable {
+			violations = append(violations, models.ConfigurationViolation{
+				Rule:     models.ConfigurationRuleOptionUnavailable,
+				GroupId:  &found.group.Id,
+				OptionId: &optionId,
+				Message:  fmt.Sprintf("%s is not available", found.option.Name),
+			})
+		}
+
+		counts[found.group.Id]++
+		chosen = append(chosen, found.option)
+	}
+
+	for _, group := range item.ModifierGroups {
+		count := counts[group.Id]
+
+		if minimum := group.MinimumSelections(); count < minimum {
+			violations = append(violations, models.ConfigurationViolation{
+				Rule:    models.ConfigurationRuleMinSelections,
+				GroupId: &group.Id,
+				Message: fmt.Sprintf("choose at least %d for %s", minimum, group.Name),
+			})
+		}
+		if group.MaxSelections != nil && count > *group.MaxSelections {
+			violations = append(violations, models.ConfigurationViolation{
+				Rule:    models.ConfigurationRuleMaxSelections,
+				GroupId: &group.Id,
+				Message: fmt.Sprintf("choose at most %d for %s", *group.MaxSelections, group.Name),
+			})
+		}
+	}
+
+	unitPrice := item.Price
+	for _, option := range chosen {
+		unitPrice += option.PriceDelta
+	}
+
+	if unitPrice < 0 {
+		violations = append(violations, models.ConfigurationViolation{
+			Rule:    models.ConfigurationRuleNegativePrice,
+			Message: "the chosen options make the price negative",
+		})
+	}
+
+	if len(violations) > 0 {
+		return nil, violations
+	}
+
+	return &models.PricedItem{
+		ItemId:     item.Id,
+		Name:       item.Name,
+		Quantity:   quantity,
+		BasePrice:  item.Price,
+		Options:    chosen,
+		UnitPrice:  unitPrice,
+		TotalPrice: unitPrice * int64(quantity),
+	}, nil
+}
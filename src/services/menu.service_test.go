@@ -0,0 +1,244 @@
+package services
+
+import (
+	"restaurant-backend/src/models"
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+)
+
+func clock(hours, minutes int) models.ClockTime {
+	return models.ClockTime(hours*60 + minutes)
+}
+
+func clockPtr(hours, minutes int) *models.ClockTime {
+	c := clock(hours, minutes)
+	return &c
+}
+
+func TestIsMenuOpen(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Monday lunch, and Friday night running into Saturday.
+	weekly := &models.Menu{
+		IsAvailable: true,
+		Windows: []*models.MenuWindow{
+			{Weekday: int(time.Monday), Start: clock(11, 0), End: clock(14, 0)},
+			{Weekday: int(time.Friday), Start: clock(22, 0), End: clock(2, 0)},
+		},
+	}
+
+	withOverrides := &models.Menu{
+		IsAvailable: true,
+		Windows:     weekly.Windows,
+		Overrides: []*models.MenuOverride{
+			// Christmas week, Monday to Sunday.
+			{StartDate: "2026-12-21", EndDate: "2026-12-27", IsAvailable: false},
+			// A Saturday, the day after a Friday night window.
+			{StartDate: "2026-11-07", EndDate: "2026-11-07", IsAvailable: false},
+			// A Tuesday evening event.
+			{StartDate: "2026-11-10", EndDate: "2026-11-10", IsAvailable: true, Start: clockPtr(17, 0), End: clockPtr(21, 0)},
+			// A Wednesday open all day.
+			{StartDate: "2026-11-11", EndDate: "2026-11-11", IsAvailable: true},
+			// A Thursday night running past midnight.
+			{StartDate: "2026-11-12", EndDate: "2026-11-12", IsAvailable: true, Start: clockPtr(20, 0), End: clockPtr(1, 0)},
+			// A Monday with shorter hours, two days long.
+			{StartDate: "2026-11-16", EndDate: "2026-11-17", IsAvailable: true, Start: clockPtr(12, 0), End: clockPtr(13, 0)},
+		},
+	}
+
+	// Saturday night running into Sunday, across both clock changes of 2026.
+	saturdayNight := &models.Menu{
+		IsAvailable: true,
+		Windows: []*models.MenuWindow{
+			{Weekday: int(time.Saturday), Start: clock(22, 0), End: clock(3, 0)},
+		},
+	}
+
+	unavailable := &models.Menu{
+		IsAvailable: false,
+		Windows:     weekly.Windows,
+	}
+
+	tests := []struct {
+		name string
+		menu *models.Menu
+		at   time.Time
+		want bool
+	}{
+		{"inside a window", weekly, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), true},
+		{"window start is included", weekly, time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC), true},
+		{"window end is excluded", weekly, time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC), false},
+		{"day without windows", weekly, time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC), false},
+		{"unavailable menu", unavailable, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), false},
+
+		{"overnight window before midnight", weekly, time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC), true},
+		{"overnight window at midnight", weekly, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), true},
+		{"overnight window after midnight", weekly, time.Date(2026, 10, 17, 1, 59, 0, 0, time.UTC), true},
+		{"overnight window end is excluded", weekly, time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC), false},
+		{"overnight window before its start", weekly, time.Date(2026, 10, 16, 21, 59, 0, 0, time.UTC), false},
+		{"overnight window on the next night", weekly, time.Date(2026, 10, 17, 23, 0, 0, 0, time.UTC), false},
+		{"overnight window does not spill into the morning of its own day", weekly, time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC), false},
+
+		{"closing override replaces the windows", withOverrides, time.Date(2026, 12, 21, 12, 0, 0, 0, time.UTC), false},
+		{"closing override covers the days between its dates", withOverrides, time.Date(2026, 12, 25, 23, 0, 0, 0, time.UTC), false},
+		{"closing override ends the night before", withOverrides, time.Date(2026, 12, 26, 1, 0, 0, 0, time.UTC), false},
+		{"windows apply again after the override", withOverrides, time.Date(2026, 12, 28, 12, 0, 0, 0, time.UTC), true},
+		{"override on the next day keeps the overnight window", withOverrides, time.Date(2026, 11, 7, 1, 0, 0, 0, time.UTC), true},
+		{"opening override inside its hours", withOverrides, time.Date(2026, 11, 10, 18, 0, 0, 0, time.UTC), true},
+		{"opening override end is excluded", withOverrides, time.Date(2026, 11, 10, 21, 0, 0, 0, time.UTC), false},
+		{"opening override without hours is open all day", withOverrides, time.Date(2026, 11, 11, 3, 0, 0, 0, time.UTC), true},
+		{"opening override without hours ends at midnight", withOverrides, time.Date(2026, 11, 12, 0, 30, 0, 0, time.UTC), false},
+		{"overnight override after midnight", withOverrides, time.Date(2026, 11, 13, 0, 30, 0, 0, time.UTC), true},
+		{"overnight override end is excluded", withOverrides, time.Date(2026, 11, 13, 1, 0, 0, 0, time.UTC), false},
+		{"opening override shortens the weekly window", withOverrides, time.Date(2026, 11, 16, 11, 30, 0, 0, time.UTC), false},
+		{"opening override replaces the weekly window", withOverrides, time.Date(2026, 11, 16, 12, 30, 0, 0, time.UTC), true},
+		{"opening override opens every day it covers", withOverrides, time.Date(2026, 11, 17, 12, 30, 0, 0, time.UTC), true},
+
+		// On 29 March 2026 Berlin skips from 02:00 to 03:00.
+		{"spring forward before the gap", saturdayNight, time.Date(2026, 3, 29, 1, 30, 0, 0, berlin), true},
+		{"spring forward closes on the wall clock", saturdayNight, time.Date(2026, 3, 28, 23, 0, 0, 0, berlin).Add(3 * time.Hour), false},
+		{"spring forward last minute", saturdayNight, time.Date(2026, 3, 28, 23, 0, 0, 0, berlin).Add(2*time.Hour + 59*time.Minute), true},
+		// On 25 October 2026 Berlin goes back from 03:00 to 02:00, so
+		// 02:30 happens twice.
+		{"fall back first 02:30", saturdayNight, time.Date(2026, 10, 24, 23, 0, 0, 0, berlin).Add(3*time.Hour + 30*time.Minute), true},
+		{"fall back second 02:30", saturdayNight, time.Date(2026, 10, 24, 23, 0, 0, 0, berlin).Add(4*time.Hour + 30*time.Minute), true},
+		{"fall back closes on the wall clock", saturdayNight, time.Date(2026, 10, 24, 23, 0, 0, 0, berlin).Add(5 * time.Hour), false},
+		{"weekday of the restaurant's time zone", weekly, time.Date(2026, 10, 16, 20, 30, 0, 0, time.UTC).In(berlin), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMenuOpen(tt.menu, tt.at); got != tt.want {
+				t.Errorf("IsMenuOpen(%s) = %v, want %v", tt.at.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	var (
+		sauceId   = uuid.New()
+		ketchupId = uuid.New()
+		mayoId    = uuid.New()
+		truffleId = uuid.New()
+		extrasId  = uuid.New()
+		cheeseId  = uuid.New()
+		baconId   = uuid.New()
+		noBunId   = uuid.New()
+	)
+
+	// A burger with one required sauce and at most two extras. Going
+	// without the bun takes a lot off the price.
+	newItem := func() *models.MenuItem {
+		maxSauces, maxExtras := 1, 2
+		return &models.MenuItem{
+			Id:          uuid.New(),
+			Name:        "Burger",
+			Price:       800,
+			IsAvailable: true,
+			ModifierGroups: []*models.ModifierGroup{
+				{Id: sauceId, Name: "Sauce", IsRequired: true, MaxSelections: &maxSauces, Options: []*models.ModifierOption{
+					{Id: ketchupId, Name: "Ketchup", IsAvailable: true},
+					{Id: mayoId, Name: "Mayo", PriceDelta: 50, IsAvailable: true},
+					{Id: truffleId, Name: "Truffle", PriceDelta: 200, IsAvailable: false},
+				}},
+				{Id: extrasId, Name: "Extras", MaxSelections: &maxExtras, Options: []*models.ModifierOption{
+					{Id: cheeseId, Name: "Cheese", PriceDelta: 100, IsAvailable: true},
+					{Id: baconId, Name: "Bacon", PriceDelta: 150, IsAvailable: true},
+					{Id: noBunId, Name: "No bun", PriceDelta: -900, IsAvailable: true},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		change    func(item *models.MenuItem)
+		optionIds []uuid.UUID
+		quantity  int
+		wantRules []string
+		wantUnit  int64
+		wantTotal int64
+	}{
+		{name: "valid", optionIds: []uuid.UUID{ketchupId, cheeseId}, quantity: 2, wantUnit: 900, wantTotal: 1800},
+		{name: "required group needs one option", optionIds: []uuid.UUID{cheeseId}, quantity: 1,
+			wantRules: []string{models.ConfigurationRuleMinSelections}},
+		{name: "min selections above one", change: func(item *models.MenuItem) { item.ModifierGroups[1].MinSelections = 2 },
+			optionIds: []uuid.UUID{ketchupId, cheeseId}, quantity: 1, wantRules: []string{models.ConfigurationRuleMinSelections}},
+		{name: "min selections met", change: func(item *models.MenuItem) { item.ModifierGroups[1].MinSelections = 2 },
+			optionIds: []uuid.UUID{ketchupId, cheeseId, baconId}, quantity: 1, wantUnit: 1050, wantTotal: 1050},
+		{name: "max selections", optionIds: []uuid.UUID{ketchupId, mayoId}, quantity: 1,
+			wantRules: []string{models.ConfigurationRuleMaxSelections}},
+		{name: "max selections of an optional group", optionIds: []uuid.UUID{ketchupId, cheeseId, baconId, noBunId}, quantity: 1,
+			wantRules: []string{models.ConfigurationRuleMaxSelections}},
+		{name: "duplicate option is counted once", optionIds: []uuid.UUID{ketchupId, ketchupId}, quantity: 1,
+			wantRules: []string{models.ConfigurationRuleDuplicateOption}},
+		{name: "unavailable option", optionIds: []uuid.UUID{truffleId}, quantity: 1,
+			wantRules: []string{models.ConfigurationRuleOptionUnavailable}},
+		{name: "unknown option", optionIds: []uuid.UUID{ketchupId, uuid.New()}, quantity: 1,
+			wantRules: []string{models.ConfigurationRuleUnknownOption}},
+		{name: "unavailable item", change: func(item *models.MenuItem) { item.IsAvailable = false },
+			optionIds: []uuid.UUID{ketchupId}, quantity: 1, wantRules: []string{models.ConfigurationRuleItemUnavailable}},
+		{name: "quantity zero", optionIds: []uuid.UUID{ketchupId}, quantity: 0,
+			wantRules: []string{models.ConfigurationRuleQuantity}},
+		{name: "quantity above the maximum", optionIds: []uuid.UUID{ketchupId}, quantity: MaxOrderQuantity + 1,
+			wantRules: []string{models.ConfigurationRuleQuantity}},
+		{name: "quantity at the maximum", optionIds: []uuid.UUID{ketchupId}, quantity: MaxOrderQuantity,
+			wantUnit: 800, wantTotal: 800 * MaxOrderQuantity},
+		{name: "price never goes negative", optionIds: []uuid.UUID{ketchupId, noBunId}, quantity: 1,
+			wantRules: []string{models.ConfigurationRuleNegativePrice}},
+		{name: "price may drop to zero", change: func(item *models.MenuItem) { item.Price = 900 },
+			optionIds: []uuid.UUID{ketchupId, noBunId}, quantity: 3, wantUnit: 0, wantTotal: 0},
+		{name: "every broken rule is reported", change: func(item *models.MenuItem) { item.IsAvailable, item.Price = false, 500 },
+			optionIds: []uuid.UUID{truffleId, mayoId, noBunId}, quantity: 0,
+			wantRules: []string{
+				models.ConfigurationRuleItemUnavailable,
+				models.ConfigurationRuleQuantity,
+				models.ConfigurationRuleOptionUnavailable,
+				models.ConfigurationRuleMaxSelections,
+				models.ConfigurationRuleNegativePrice,
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := newItem()
+			if tt.change != nil {
+				tt.change(item)
+			}
+
+			priced, violations := ValidateConfiguration(item, tt.optionIds, tt.quantity)
+
+			rules := []string{}
+			for _, violation := range violations {
+				rules = append(rules, violation.Rule)
+			}
+			if len(tt.wantRules) > 0 || len(rules) > 0 {
+				if !slices.Equal(rules, tt.wantRules) {
+					t.Fatalf("rules = %v, want %v", rules, tt.wantRules)
+				}
+				if priced != nil {
+					t.Fatalf("priced item returned with violations")
+				}
+				return
+			}
+
+			if priced == nil {
+				t.Fatal("no priced item without violations")
+			}
+			if priced.UnitPrice != tt.wantUnit || priced.TotalPrice != tt.wantTotal {
+				t.Errorf("prices = %d and %d, want %d and %d", priced.UnitPrice, priced.TotalPrice, tt.wantUnit, tt.wantTotal)
+			}
+			if priced.BasePrice != item.Price || priced.Quantity != tt.quantity || len(priced.Options) != len(tt.optionIds) {
+				t.Errorf("priced item = %+v", priced)
+			}
+		})
+	}
+}
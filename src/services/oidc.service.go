@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/oidc"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownOIDCProvider = errors.New("unknown sign-in provider")
+	ErrInvalidOIDCState    = errors.New("sign-in request is invalid or expired")
+	ErrOIDCNoAccount       = errors.New("no account matches this identity")
+	ErrLastSignInMethod    = errors.New("cannot remove the only way to sign in")
+	ErrOIDCLinkRequired    = errors.New("sign in and link this provider to your account first")
+	ErrOIDCIdentityTaken   = errors.New("this sign-in is already linked to another account")
+)
+
+// OIDCService signs users in through external OpenID Connect providers
+// and links the identities to user accounts.
+type OIDCService struct {
+	clients      map[string]*oidc.Client
+	identityRepo *repositories.IdentityRepository
+	userRepo     *repositories.UserRepository
+	restaurants  *RestaurantService
+	config       *config.OIDCConfig
+	appConfig    *config.AppConfig
+}
+
+func NewOIDCService(ctx *models.AppContext) *OIDCService {
+	clients := map[string]*oidc.Client{}
+	for name, provider := range ctx.Config.OIDC.Providers {
+		clients[name] = oidc.NewClient(provider, CallbackURL(ctx.Config.OIDC, name))
+	}
+
+	return &OIDCService{
+		clients:      clients,
+		identityRepo: repositories.NewIdentityRepository(ctx.DB),
+		userRepo:     repositories.NewUserRepository(ctx.DB),
+		restaurants:  NewRestaurantService(ctx),
+		config:       ctx.Config.OIDC,
+		appConfig:    ctx.Config.App,
+	}
+}
+
+func CallbackURL(c *config.OIDCConfig, provider string) string {
+	return strings.TrimRight(c.RedirectBaseURL, "/") + "/api/auth/oidc/" + provider + "/callback"
+}
+
+func (oc *OIDCService) Providers() []*config.OIDCProviderConfig {
+	providers := make([]*config.OIDCProviderConfig, 0, len(oc.clients))
+	for _, client := range oc.clients {
+		providers = append(providers, client.Provider())
+	}
+
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+
+	return providers
+}
+
+// Begin stores a pending login and returns the provider URL to redirect
+// the browser to, together with the state the callback must echo. With a
+// linkUser the callback links the provider to that user instead of
+// signing in.
+func (oc *OIDCService) Begin(ctx context.Context, provider, returnTo string, linkUser *models.User) (string, string, error) {
+	client, ok := oc.clients[provider]
+	if !ok {
+		return "", "", ErrUnknownOIDCProvider
+	}
+
+	state := utils.GenerateRandomToken()
+	nonce := oidc.GenerateNonce()
+	verifier := oidc.GenerateCodeVerifier()
+
+	authURL, err := client.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return "", "", err
+	}
+
+	pending := &models.OIDCLoginState{
+		StateHash:    utils.HashString(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ReturnTo:     returnTo,
+		ExpiresAt:    time.Now().Add(time.Duration(oc.config.StateTTLMinutes) * time.Minute),
+	}
+	if linkUser != nil {
+		pending.LinkUserId = &linkUser.Id
+	}
+
+	err = oc.identityRepo.CreateState(pending)
+	if err != nil {
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+// Complete finishes a login or a link from the provider callback. It
+// returns the signed in or linking user and the pending request, which
+// says where the browser asked to go afterwards and whether it was a link.
+// The pending request is also returned with errors once the state is
+// known to be valid.
+func (oc *OIDCService) Complete(ctx context.Context, provider, state, code string) (*models.User, *models.OIDCLoginState, error) {
+	client, ok := oc.clients[provider]
+	if !ok {
+		return nil, nil, ErrUnknownOIDCProvider
+	}
+
+	pending, err := oc.identityRepo.ConsumeState(utils.HashString(state))
+	if err != nil {
+		return nil, nil, err
+	}
+	if pending == nil || pending.Provider != provider {
+		return nil, nil, ErrInvalidOIDCState
+	}
+
+	tokens, err := client.Exchange(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		return nil, pending, err
+	}
+
+	claims, err := client.VerifyIDToken(ctx, tokens.IDToken, pending.Nonce)
+	if err != nil {
+		return nil, pending, err
+	}
+
+	var user *models.User
+	if pending.LinkUserId != nil {
+		user, err = oc.linkUser(*pending.LinkUserId, provider, claims)
+	} else {
+		user, err = oc.resolveUser(provider, claims)
+	}
+	if err != nil {
+		return nil, pending, err
+	}
+
+	if !user.IsActive {
+		return nil, pending, ErrAccountDisabled
+	}
+
+	return user, pending, nil
+}
+
+// linkUser links the identity to the user who started the link while
+// signed in. An identity already linked to someone else is refused.
+func (oc *OIDCService) linkUser(userId uuid.UUID, provider string, claims *oidc.IDTokenClaims) (*models.User, error) {
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+
+	user, err := oc.userRepo.GetUserById(userId.String())
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrAccountDisabled
+	}
+
+	identity, err := oc.identityRepo.GetIdentity(provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity == nil {
+		identity = &models.UserIdentity{
+			UserId:   user.Id,
+			Provider: provider,
+			Subject:  claims.Subject,
+			Email:    email,
+		}
+
+		if err := oc.identityRepo.CreateIdentity(identity); err != nil {
+			return nil, err
+		}
+	} else if identity.UserId != user.Id {
+		return nil, ErrOIDCIdentityTaken
+	}
+
+	if err := oc.identityRepo.TouchIdentity(identity.Id, email); err != nil {
+		log.Printf("ERROR: Failed to update identity %s: %v", identity.Id, err)
+	}
+
+	return user, nil
+}
+
+// resolveUser finds the staff account for an identity. Unknown identities
+// are never linked to an existing account by their address, whoever
+// controls the address at the provider would get into the account. The
+// user has to sign in and link the provider first. New accounts are only
+// created for verified addresses when signup is allowed.
+func (oc *OIDCService) resolveUser(provider string, claims *oidc.IDTokenClaims) (*models.User, error) {
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+
+	identity, err := oc.identityRepo.GetIdentity(provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity != nil {
+		user, err := oc.userRepo.GetUserById(identity.UserId.String())
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, ErrAccountDisabled
+		}
+		if !user.IsStaff() {
+			return nil, ErrOIDCNoAccount
+		}
+
+		if err := oc.identityRepo.TouchIdentity(identity.Id, email); err != nil {
+			log.Printf("ERROR: Failed to update identity %s: %v", identity.Id, err)
+		}
+
+		return user, nil
+	}
+
+	if email == "" || !bool(claims.EmailVerified) {
+		return nil, ErrOIDCNoAccount
+	}
+
+	user, err := oc.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		if !user.IsStaff() {
+			return nil, ErrOIDCNoAccount
+		}
+		return nil, ErrOIDCLinkRequired
+	}
+
+	if !oc.config.AllowSignup || !oc.appConfig.PublicRegistrationEnabled {
+		return nil, ErrOIDCNoAccount
+	}
+
+	if user, err = oc.createUser(email, claims.Name); err != nil {
+		return nil, err
+	}
+
+	identity = &models.UserIdentity{
+		UserId:   user.Id,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    email,
+	}
+
+	if err := oc.identityRepo.CreateIdentity(identity); err != nil {
+		return nil, err
+	}
+
+	if err := oc.identityRepo.TouchIdentity(identity.Id, email); err != nil {
+		log.Printf("ERROR: Failed to update identity %s: %v", identity.Id, err)
+	}
+
+	return user, nil
+}
+
+// createUser registers an account without a password, the user can set
+// one later through the password reset flow.
+func (oc *OIDCService) createUser(email, name string) (*models.User, error) {
+	name = strings.TrimSpace(name)
+	if len(name) < 2 {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	if runes := []rune(name); len(runes) > 50 {
+		name = string(runes[:50])
+	}
+
+	role, err := DefaultUserRole(oc.appConfig)
+	if err != nil {
+		role = models.RoleWaiter
+	}
+
+	verifiedAt := time.Now()
+	user := &models.User{
+		Name:            name,
+		Email:           email,
+		Role:            role,
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	if err := oc.userRepo.CreateUser(user); err != nil {
+		return nil, err
+	}
+
+	if err := oc.restaurants.JoinDefaultRestaurant(user); err != nil {
+		log.Printf("ERROR: Failed to add %s to the default restaurant: %v", user.Id, err)
+	}
+
+	return user, nil
+}
+
+func (oc *OIDCService) GetUserIdentities(user *models.User) ([]*models.UserIdentity, error) {
+	return oc.identityRepo.GetUserIdentities(user.Id)
+}
+
+// UnlinkIdentity refuses to remove the last identity of an account that
+// has no password.
+func (oc *OIDCService) UnlinkIdentity(user *models.User, identityId uuid.UUID) (bool, error) {
+	if user.Password == "" {
+		identities, err := oc.identityRepo.GetUserIdentities(user.Id)
+		if err != nil {
+			return false, err
+		}
+		if len(identities) <= 1 {
+			return false, ErrLastSignInMethod
+		}
+	}
+
+	return oc.identityRepo.DeleteIdentity(user.Id, identityId)
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"strings"
+	"unicode"
+)
+
+// Name and email fragments shorter than this are too common to reject on.
+const minPersonalInfoLength = 3
+
+var blocklist = map[string]struct{}{}
+
+// PasswordPolicyService checks new passwords against the rules configured
+// in AppConfig. Passwords are checked and hashed exactly as sent, without
+// trimming.
+type PasswordPolicyService struct {
+	config *config.AppConfig
+}
+
+func NewPasswordPolicyService(ctx *models.AppContext) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		config: ctx.Config.App,
+	}
+}
+
+// Validate returns every rule the password breaks, name and email may be
+// empty when they are not known yet.
+func (ps *PasswordPolicyService) Validate(password, name, email string) []models.PasswordViolation {
+	violations := []models.PasswordViolation{}
+
+	if password == "" {
+		return append(violations, models.PasswordViolation{
+			Rule:    models.PasswordRuleRequired,
+			Message: "password is required",
+		})
+	}
+
+	length := len([]rune(password))
+	if length < ps.config.PasswordMinLength {
+		violations = append(violations, models.PasswordViolation{
+			Rule:    models.PasswordRuleMinLength,
+			Message: fmt.Sprintf("password must be at least %d characters long", ps.config.PasswordMinLength),
+		})
+	}
+	if ps.config.PasswordMaxLength > 0 && len(password) > ps.config.PasswordMaxLength {
+		violations = append(violations, models.PasswordViolation{
+			Rule:    models.PasswordRuleMaxLength,
+			Message: fmt.Sprintf("password must be no more than %d bytes long", ps.config.PasswordMaxLength),
+		})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if ps.config.PasswordRequireUppercase && !hasUpper {
+		violations = append(violations, models.PasswordViolation{
+			Rule:    models.PasswordRuleUppercase,
+			Message: "password must contain an uppercase letter",
+		})
+	}
+	if ps.config.PasswordRequireLowercase && !hasLower {
+		violations = append(violations, models.PasswordViolation{
+			Rule:    models.PasswordRuleLowercase,
+			Message: "password must contain a lowercase letter",
+		})
+	}
+	if ps.config.PasswordRequireDigit && !hasDigit {
+		violations = append(violations, models.PasswordViolation{
+			Rule:    models.PasswordRuleDigit,
+			Message: "password must contain a digit",
+		})
+	}
+	if ps.config.PasswordRequireSymbol && !hasSymbol {
+		violations = append(violations, models.PasswordViolation{
+			Rule:    models.PasswordRuleSymbol,
+			Message: "password must contain a symbol",
+		})
+	}
+
+	lowered := strings.ToLower(password)
+
+	if _, blocked := blocklist[lowered]; blocked {
+		violations = append(violations, models.PasswordViolation{
+			Rule:    models.PasswordRuleBlocklisted,
+			Message: "password is too common, choose a less predictable one",
+		})
+	}
+
+	if ps.config.PasswordCheckPersonalInfo {
+		for _, part := range strings.Fields(strings.ToLower(name)) {
+			if len(part) >= minPersonalInfoLength && strings.Contains(lowered, part) {
+				violations = append(violations, models.PasswordViolation{
+					Rule:    models.PasswordRuleContainsName,
+					Message: "password must not contain your name",
+				})
+				break
+			}
+		}
+
+		local, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+		if len(local) >= minPersonalInfoLength && strings.Contains(lowered, local) {
+			violations = append(violations, models.PasswordViolation{
+				Rule:    models.PasswordRuleContainsEmail,
+				Message: "password must not contain your email address",
+			})
+		}
+	}
+
+	return violations
+}
+
+// LoadPasswordBlocklist reads the configured blocklist, or the built in
+// one when no file is set. It is called once at startup, a configured file
+// that cannot be read is an error.
+func LoadPasswordBlocklist(appConfig *config.AppConfig) error {
+	if !appConfig.PasswordBlocklistEnabled {
+		return nil
+	}
+
+	var source io.Reader = strings.NewReader(config.DefaultPasswordBlocklist)
+	if appConfig.PasswordBlocklistFile != "" {
+		file, err := os.Open(appConfig.PasswordBlocklistFile)
+		if err != nil {
+			return fmt.Errorf("error opening password blocklist: %v", err)
+		}
+		defer file.Close()
+		source = file
+	}
+
+	entries, err := parseBlocklist(source)
+	if err != nil {
+		return fmt.Errorf("error reading password blocklist: %v", err)
+	}
+
+	blocklist = entries
+	log.Printf("Loaded %d blocklisted passwords", len(entries))
+
+	return nil
+}
+
+// parseBlocklist reads one password per line, skipping blanks and lines
+// starting with #.
+func parseBlocklist(source io.Reader) (map[string]struct{}, error) {
+	entries := map[string]struct{}{}
+
+	scanner := bufio.NewScanner(source)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries[strings.ToLower(line)] = struct{}{}
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrErasureOutsideOrganization is returned when the requester also works
+// for another organization, whose memberships one organization must not
+// remove.
+var ErrErasureOutsideOrganization = errors.New("user also works for another organization")
+
+type PrivacyService struct {
+	customerRestaurantId uuid.UUID
+	privacyRepo          *repositories.PrivacyRepository
+	restaurantRepo       *repositories.RestaurantRepository
+	identityRepo         *repositories.IdentityRepository
+	sessionRepo          *repositories.SessionRepository
+	auditRepo            *repositories.AuditRepository
+}
+
+// PrivacyCustomerRestaurantId is the restaurant whose queue receives the
+// requests of customer accounts, uuid.Nil when none does.
+func PrivacyCustomerRestaurantId(appConfig *config.AppConfig) (uuid.UUID, error) {
+	if appConfig.PrivacyCustomerRestaurantId == "" {
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(appConfig.PrivacyCustomerRestaurantId)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("PRIVACY_CUSTOMER_RESTAURANT_ID %q is not a valid id: %v", appConfig.PrivacyCustomerRestaurantId, err)
+	}
+
+	return id, nil
+}
+
+func NewPrivacyService(ctx *models.AppContext) *PrivacyService {
+	// Checked at startup.
+	customerRestaurantId, _ := PrivacyCustomerRestaurantId(ctx.Config.App)
+
+	return &PrivacyService{
+		customerRestaurantId: customerRestaurantId,
+		privacyRepo:          repositories.NewPrivacyRepository(ctx.DB),
+		restaurantRepo:       repositories.NewRestaurantRepository(ctx.DB),
+		identityRepo:         repositories.NewIdentityRepository(ctx.DB),
+		sessionRepo:          repositories.NewSessionRepository(ctx.DB),
+		auditRepo:            repositories.NewAuditRepository(ctx.DB),
+	}
+}
+
+// Export collects everything stored about the user.
+func (ps *PrivacyService) Export(user *models.User) (*models.DataExport, error) {
+	export := &models.DataExport{
+		GeneratedAt: time.Now(),
+		User:        user,
+	}
+
+	var err error
+
+	if export.Restaurants, err = ps.restaurantRepo.GetUserRestaurants(user.Id); err != nil {
+		return nil, err
+	}
+	if export.Identities, err = ps.identityRepo.GetUserIdentities(user.Id); err != nil {
+		return nil, err
+	}
+	if export.Sessions, err = ps.sessionRepo.GetActiveSessionsByUserId(user.Id); err != nil {
+		return nil, err
+	}
+	if export.Activity, err = ps.auditRepo.GetUserEntries(user.Id); err != nil {
+		return nil, err
+	}
+	if export.DataRequests, err = ps.privacyRepo.GetUserRequests(user.Id); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// WriteArchive writes the export as a ZIP bundle with one JSON file per
+// section.
+func (ps *PrivacyService) WriteArchive(w io.Writer, export *models.DataExport) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.User},
+		{"restaurants.json", export.Restaurants},
+		{"identities.json", export.Identities},
+		{"sessions.json", export.Sessions},
+		{"activity.json", export.Activity},
+		{"data_requests.json", export.DataRequests},
+	}
+
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.GeneratedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// RequestErasure queues an erasure request and reports false when the user
+// already has one pending.
+func (ps *PrivacyService) RequestErasure(user *models.User, reason string) (*models.DataRequest, bool, error) {
+	request := &models.DataRequest{
+		UserId: user.Id,
+		Type:   models.DataRequestTypeErasure,
+		Reason: reason,
+	}
+
+	created, err := ps.privacyRepo.CreateRequest(request)
+	if err != nil || !created {
+		return nil, false, err
+	}
+
+	return request, true, nil
+}
+
+// ListRequests returns the restaurant's queue. Requests of customer
+// accounts are only in the queue of PRIVACY_CUSTOMER_RESTAURANT_ID.
+func (ps *PrivacyService) ListRequests(restaurantId uuid.UUID, status string, limit, offset int) ([]*models.DataRequest, int, error) {
+	return ps.privacyRepo.ListRequests(restaurantId, ps.handlesCustomers(restaurantId), status, limit, offset)
+}
+
+func (ps *PrivacyService) GetRequest(restaurantId, id uuid.UUID) (*models.DataRequest, error) {
+	return ps.privacyRepo.GetRestaurantRequest(restaurantId, ps.handlesCustomers(restaurantId), id)
+}
+
+func (ps *PrivacyService) handlesCustomers(restaurantId uuid.UUID) bool {
+	return ps.customerRestaurantId != uuid.Nil && restaurantId == ps.customerRestaurantId
+}
+
+// Complete erases the requester's personal data and reports false when the
+// request was processed in the meantime. Staff who also work for another
+// organization than the restaurant's are refused with
+// ErrErasureOutsideOrganization, that organization has to let them go
+// first.
+func (ps *PrivacyService) Complete(restaurant *models.Restaurant, request *models.DataRequest, processor *models.User, note string) (bool, error) {
+	elsewhere, err := ps.restaurantRepo.HasMembershipOutside(request.UserId, restaurant.OrganizationId)
+	if err != nil {
+		return false, err
+	}
+	if elsewhere {
+		return false, ErrErasureOutsideOrganization
+	}
+
+	return ps.privacyRepo.CompleteErasure(request, processor.Id, note)
+}
+
+// Reject closes the request without touching any data and reports false
+// when the request was processed in the meantime.
+func (ps *PrivacyService) Reject(request *models.DataRequest, processor *models.User, note string) (bool, error) {
+	rejected, err := ps.privacyRepo.RejectRequest(request.Id, processor.Id, note)
+	if err != nil || !rejected {
+		return false, err
+	}
+
+	now := time.Now()
+	request.Status = models.DataRequestStatusRejected
+	request.Note = note
+	request.ProcessedBy = &processor.Id
+	request.ProcessedAt = &now
+
+	return true, nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidTimezone = errors.New("unknown timezone")
+
+type RestaurantService struct {
+	restaurantRepo *repositories.RestaurantRepository
+	config         *config.AppConfig
+}
+
+func NewRestaurantService(ctx *models.AppContext) *RestaurantService {
+	return &RestaurantService{
+		restaurantRepo: repositories.NewRestaurantRepository(ctx.DB),
+		config:         ctx.Config.App,
+	}
+}
+
+// JoinDefaultRestaurant makes a self registered user a member of the
+// configured default restaurant when DEFAULT_RESTAURANT_AUTO_JOIN is set.
+// Otherwise the account starts out with no restaurant until someone adds
+// it.
+func (rs *RestaurantService) JoinDefaultRestaurant(user *models.User) error {
+	if !rs.config.DefaultRestaurantAutoJoin || rs.config.DefaultRestaurantId == "" {
+		return nil
+	}
+
+	restaurantId, err := uuid.Parse(rs.config.DefaultRestaurantId)
+	if err != nil {
+		log.Printf("ERROR: Invalid DEFAULT_RESTAURANT_ID %q: %v", rs.config.DefaultRestaurantId, err)
+		return nil
+	}
+
+	_, err = rs.restaurantRepo.AddMember(&models.Membership{
+		UserId:       user.Id,
+		RestaurantId: restaurantId,
+		Role:         user.Role,
+	})
+	return err
+}
+
+func (rs *RestaurantService) GetUserRestaurants(user *models.User) ([]*models.RestaurantMembership, error) {
+	return rs.restaurantRepo.GetUserRestaurants(user.Id)
+}
+
+// CreateOrganization starts a new organization with its first restaurant,
+// owned by the creator.
+func (rs *RestaurantService) CreateOrganization(creator *models.User, name, restaurantName, timezone string) (*models.Organization, *models.Restaurant, error) {
+	if err := validateTimezone(timezone); err != nil {
+		return nil, nil, err
+	}
+
+	organization := &models.Organization{Name: name}
+	if err := rs.restaurantRepo.CreateOrganization(organization); err != nil {
+		return nil, nil, err
+	}
+
+	restaurant, err := rs.CreateRestaurant(creator, organization.Id, restaurantName, timezone)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return organization, restaurant, nil
+}
+
+// CreateRestaurant adds a restaurant to an organization and makes the
+// creator its owner.
+func (rs *RestaurantService) CreateRestaurant(creator *models.User, organizationId uuid.UUID, name, timezone string) (*models.Restaurant, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if err := validateTimezone(timezone); err != nil {
+		return nil, err
+	}
+
+	restaurant := &models.Restaurant{
+		OrganizationId: organizationId,
+		Name:           name,
+		Timezone:       timezone,
+	}
+
+	if err := rs.restaurantRepo.CreateRestaurant(restaurant); err != nil {
+		return nil, err
+	}
+
+	_, err := rs.restaurantRepo.AddMember(&models.Membership{
+		UserId:       creator.Id,
+		RestaurantId: restaurant.Id,
+		Role:         models.RoleOwner,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restaurant, nil
+}
+
+func validateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+	return nil
+}
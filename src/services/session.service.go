@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const SessionCookieName = "dashboard-cookie"
+
+// Last-seen updates are throttled so that every authenticated request
+// does not turn into a write.
+const sessionTouchInterval = time.Minute
+
+// Guests are kept this long before their session is looked at, it is
+// stored right after the account.
+const guestCleanupGrace = time.Hour
+
+type SessionService struct {
+	sessionRepo *repositories.SessionRepository
+	refreshRepo *repositories.RefreshTokenRepository
+	userRepo    *repositories.UserRepository
+	config      *config.AppConfig
+}
+
+func NewSessionService(ctx *models.AppContext) *SessionService {
+	return &SessionService{
+		sessionRepo: repositories.NewSessionRepository(ctx.DB),
+		refreshRepo: repositories.NewRefreshTokenRepository(ctx.DB),
+		userRepo:    repositories.NewUserRepository(ctx.DB),
+		config:      ctx.Config.App,
+	}
+}
+
+func (ss *SessionService) ttl() time.Duration {
+	return time.Duration(ss.config.SessionTTLHours) * time.Hour
+}
+
+// The cookie carries a random token; only its peppered hash is stored.
+func (ss *SessionService) hashToken(token string) string {
+	return utils.HashString(fmt.Sprintf("%s:%s", token, ss.config.CookieSecretKey))
+}
+
+// CreateSession stores a new session for the user and returns the plain
+// token that has to be handed to the client.
+func (ss *SessionService) CreateSession(userId uuid.UUID, r *http.Request) (string, *models.Session, error) {
+	token := utils.GenerateRandomToken()
+
+	session := &models.Session{
+		UserId:    userId,
+		TokenHash: ss.hashToken(token),
+		UserAgent: utils.Truncate(r.UserAgent(), 512),
+		IPAddress: utils.Truncate(utils.GetClientIP(r), 64),
+		ExpiresAt: time.Now().Add(ss.ttl()),
+	}
+
+	if err := ss.sessionRepo.CreateSession(session); err != nil {
+		return "", nil, err
+	}
+
+	return token, session, nil
+}
+
+// ResolveSession returns the active session for the token, or nil if it
+// is unknown or expired. Each use slides the expiry forward.
+func (ss *SessionService) ResolveSession(token string) (*models.Session, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	session, err := ss.sessionRepo.GetActiveSessionByTokenHash(ss.hashToken(token))
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		session.LastSeenAt = now
+		session.ExpiresAt = now.Add(ss.ttl())
+
+		if err := ss.sessionRepo.TouchSession(session.Id, session.LastSeenAt, session.ExpiresAt); err != nil {
+			return nil, err
+		}
+	}
+
+	return session, nil
+}
+
+func (ss *SessionService) DeleteSession(id uuid.UUID) error {
+	return ss.sessionRepo.DeleteSession(id)
+}
+
+func (ss *SessionService) GetUserSessions(userId uuid.UUID) ([]*models.Session, error) {
+	return ss.sessionRepo.GetActiveSessionsByUserId(userId)
+}
+
+func (ss *SessionService) RevokeUserSession(id, userId uuid.UUID) (bool, error) {
+	return ss.sessionRepo.DeleteUserSession(id, userId)
+}
+
+// RevokeAllUserSessions signs the user out on every device, including
+// clients that hold bearer refresh tokens.
+func (ss *SessionService) RevokeAllUserSessions(userId uuid.UUID) (int64, error) {
+	revoked, err := ss.sessionRepo.DeleteSessionsByUserId(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	revokedTokens, err := ss.refreshRepo.RevokeUserTokens(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return revoked + revokedTokens, nil
+}
+
+func (ss *SessionService) SetSessionCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(ss.ttl().Seconds()),
+	})
+}
+
+func (ss *SessionService) ClearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1,
+	})
+}
+
+func (ss *SessionService) CleanupExpiredSessions() (int64, error) {
+	return ss.sessionRepo.DeleteExpiredSessions()
+}
+
+// StartCleanup periodically removes expired sessions and refresh tokens,
+// and the guest accounts whose sessions have ended, in the background.
+func (ss *SessionService) StartCleanup() {
+	interval := time.Duration(ss.config.SessionCleanupIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			deleted, err := ss.CleanupExpiredSessions()
+			if err != nil {
+				log.Printf("ERROR: Session cleanup failed: %v", err)
+				continue
+			}
+
+			if deleted > 0 {
+				log.Printf("Removed %d expired sessions", deleted)
+			}
+
+			deletedGuests, err := ss.userRepo.DeleteEndedGuests(time.Now().Add(-guestCleanupGrace))
+			if err != nil {
+				log.Printf("ERROR: Guest cleanup failed: %v", err)
+			} else if deletedGuests > 0 {
+				log.Printf("Removed %d ended guest accounts", deletedGuests)
+			}
+
+			deletedTokens, err := ss.refreshRepo.DeleteExpiredTokens()
+			if err != nil {
+				log.Printf("ERROR: Refresh token cleanup failed: %v", err)
+				continue
+			}
+
+			if deletedTokens > 0 {
+				log.Printf("Removed %d expired refresh tokens", deletedTokens)
+			}
+		}
+	}()
+}
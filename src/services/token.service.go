@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/utils"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const accessTokenType = "access"
+
+// minAccessTokenSecretLength is 32 bytes, the size of the HMAC-SHA256 key.
+const minAccessTokenSecretLength = 32
+
+// insecureAccessTokenSecrets were shipped as defaults or examples, anyone
+// could forge tokens signed with them.
+var insecureAccessTokenSecrets = []string{"secret-access-token", "your-access-token-secret"}
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+)
+
+type TokenService struct {
+	refreshRepo *repositories.RefreshTokenRepository
+	config      *config.AppConfig
+}
+
+func NewTokenService(ctx *models.AppContext) *TokenService {
+	return &TokenService{
+		refreshRepo: repositories.NewRefreshTokenRepository(ctx.DB),
+		config:      ctx.Config.App,
+	}
+}
+
+// CheckAccessTokenSecret refuses a missing, well known or short
+// ACCESS_TOKEN_SECRET, the server must not start with one.
+func CheckAccessTokenSecret(appConfig *config.AppConfig) error {
+	secret := appConfig.AccessTokenSecret
+
+	if secret == "" {
+		return fmt.Errorf("ACCESS_TOKEN_SECRET is not set")
+	}
+	if slices.Contains(insecureAccessTokenSecrets, secret) {
+		return fmt.Errorf("ACCESS_TOKEN_SECRET is a published example, generate a random one")
+	}
+	if len(secret) < minAccessTokenSecretLength {
+		return fmt.Errorf("ACCESS_TOKEN_SECRET must be at least %d characters long", minAccessTokenSecretLength)
+	}
+
+	return nil
+}
+
+func (ts *TokenService) AccessTokenTTL() time.Duration {
+	return time.Duration(ts.config.AccessTokenTTLMinutes) * time.Minute
+}
+
+// IssueTokens starts a new refresh token family for the user.
+func (ts *TokenService) IssueTokens(userId uuid.UUID, r *http.Request) (*models.TokenResponse, error) {
+	return ts.issue(userId, uuid.New(), r)
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already
+// rotated is treated as theft and revokes the whole family.
+func (ts *TokenService) Refresh(refreshToken string, r *http.Request) (*models.TokenResponse, error) {
+	tokenHash := utils.HashString(refreshToken)
+
+	used, err := ts.refreshRepo.UseToken(tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if used != nil {
+		return ts.issue(used.UserId, used.FamilyId, r)
+	}
+
+	existing, err := ts.refreshRepo.GetTokenByHash(tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil && existing.UsedAt != nil && existing.RevokedAt == nil {
+		log.Printf("SECURITY: Refresh token reuse detected for user %s (family=%s, ip=%s), revoking family",
+			existing.UserId, existing.FamilyId, utils.GetClientIP(r))
+
+		if err := ts.refreshRepo.RevokeFamily(existing.FamilyId); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrRefreshTokenReused
+	}
+
+	return nil, ErrInvalidRefreshToken
+}
+
+// Revoke ends the family the refresh token belongs to.
+func (ts *TokenService) Revoke(refreshToken string) (bool, error) {
+	existing, err := ts.refreshRepo.GetTokenByHash(utils.HashString(refreshToken))
+	if err != nil || existing == nil {
+		return false, err
+	}
+
+	return true, ts.refreshRepo.RevokeFamily(existing.FamilyId)
+}
+
+func (ts *TokenService) RevokeUserTokens(userId uuid.UUID) (int64, error) {
+	return ts.refreshRepo.RevokeUserTokens(userId)
+}
+
+func (ts *TokenService) CleanupExpiredTokens() (int64, error) {
+	return ts.refreshRepo.DeleteExpiredTokens()
+}
+
+// ParseAccessToken validates the signature, type and expiry of an access
+// token and returns the user id it was issued to.
+func (ts *TokenService) ParseAccessToken(token string) (uuid.UUID, error) {
+	var claims models.AccessTokenClaims
+
+	if err := utils.ParseJWT(token, ts.config.AccessTokenSecret, &claims); err != nil {
+		return uuid.Nil, err
+	}
+
+	if claims.Type != accessTokenType || time.Now().Unix() >= claims.ExpiresAt {
+		return uuid.Nil, utils.ErrInvalidToken
+	}
+
+	userId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, utils.ErrInvalidToken
+	}
+
+	return userId, nil
+}
+
+func (ts *TokenService) issue(userId, familyId uuid.UUID, r *http.Request) (*models.TokenResponse, error) {
+	now := time.Now()
+
+	accessToken, err := utils.SignJWT(models.AccessTokenClaims{
+		Subject:   userId.String(),
+		TokenId:   uuid.New().String(),
+		Type:      accessTokenType,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ts.AccessTokenTTL()).Unix(),
+	}, ts.config.AccessTokenSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken := utils.GenerateRandomToken()
+
+	err = ts.refreshRepo.CreateToken(&models.RefreshToken{
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: utils.HashString(refreshToken),
+		UserAgent: utils.Truncate(r.UserAgent(), 512),
+		IPAddress: utils.Truncate(utils.GetClientIP(r), 64),
+		ExpiresAt: now.AddDate(0, 0, ts.config.RefreshTokenTTLDays),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenResponse{
+		Success:      true,
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(ts.AccessTokenTTL().Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+
+	"github.com/google/uuid"
+)
+
+type TranslationService struct {
+	translationRepo *repositories.TranslationRepository
+}
+
+func NewTranslationService(ctx *models.AppContext) *TranslationService {
+	return &TranslationService{
+		translationRepo: repositories.NewTranslationRepository(ctx.DB),
+	}
+}
+
+// translatable is one text of a response, to be replaced by the first
+// translation found along the locale chain.
+type translatable struct {
+	entityType string
+	entityId   uuid.UUID
+	field      string
+	text       *string
+}
+
+type translationKey struct {
+	entityType string
+	entityId   uuid.UUID
+	field      string
+	locale     string
+}
+
+// TranslateMenu translates the categories with their items, modifier groups
+// and options, and the menus, in place. Locales is the fallback chain from
+// utils.GetLocales, texts without a translation stay as they are.
+func (ts *TranslationService) TranslateMenu(restaurantId uuid.UUID, locales []string, categories []*models.MenuCategory, menus []*models.Menu) error {
+	texts := []translatable{}
+
+	for _, category := range categories {
+		texts = append(texts,
+			translatable{models.TranslationEntityMenuCategory, category.Id, "name", &category.Name},
+			translatable{models.TranslationEntityMenuCategory, category.Id, "description", &category.Description})
+		for _, item := range category.Items {
+			texts = appendItemTexts(texts, item)
+		}
+	}
+
+	for _, menu := range menus {
+		texts = append(texts,
+			translatable{models.TranslationEntityMenu, menu.Id, "name", &menu.Name},
+			translatable{models.TranslationEntityMenu, menu.Id, "description", &menu.Description})
+	}
+
+	return ts.translate(restaurantId, locales, texts)
+}
+
+// TranslateItem translates the item with its modifier groups and options
+// in place, see TranslateMenu.
+func (ts *TranslationService) TranslateItem(restaurantId uuid.UUID, locales []string, item *models.MenuItem) error {
+	return ts.translate(restaurantId, locales, appendItemTexts(nil, item))
+}
+
+func appendItemTexts(texts []translatable, item *models.MenuItem) []translatable {
+	texts = append(texts,
+		translatable{models.TranslationEntityMenuItem, item.Id, "name", &item.Name},
+		translatable{models.TranslationEntityMenuItem, item.Id, "description", &item.Description})
+
+	for _, group := range item.ModifierGroups {
+		texts = append(texts, translatable{models.TranslationEntityModifierGroup, group.Id, "name", &group.Name})
+		for _, option := range group.Options {
+			texts = append(texts, translatable{models.TranslationEntityModifierOption, option.Id, "name", &option.Name})
+		}
+	}
+
+	return texts
+}
+
+func (ts *TranslationService) translate(restaurantId uuid.UUID, locales []string, texts []translatable) error {
+	if len(locales) == 0 || len(texts) == 0 {
+		return nil
+	}
+
+	seen := map[uuid.UUID]bool{}
+	entityIds := []uuid.UUID{}
+	for _, text := range texts {
+		if !seen[text.entityId] {
+			seen[text.entityId] = true
+			entityIds = append(entityIds, text.entityId)
+		}
+	}
+
+	translations, err := ts.translationRepo.GetValues(restaurantId, entityIds, locales)
+	if err != nil {
+		return err
+	}
+	if len(translations) == 0 {
+		return nil
+	}
+
+	values := make(map[translationKey]string, len(translations))
+	for _, translation := range translations {
+		values[translationKey{translation.EntityType, translation.EntityId, translation.Field, translation.Locale}] = translation.Value
+	}
+
+	// Shared groups and options show up once per item, so a text may be
+	// reached twice. Looking up by id rather than by text keeps that
+	// harmless.
+	for _, text := range texts {
+		for _, locale := range locales {
+			if value, ok := values[translationKey{text.entityType, text.entityId, text.field, locale}]; ok {
+				*text.text = value
+				break
+			}
+		}
+	}
+
+	return nil
+}
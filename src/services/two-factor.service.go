@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"restaurant-backend/src/config"
+	"restaurant-backend/src/models"
+	"restaurant-backend/src/repositories"
+	"restaurant-backend/src/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	mfaChallengeType  = "mfa"
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrInvalidMFAChallenge = errors.New("two-factor challenge is invalid or expired")
+	ErrInvalidMFACode      = errors.New("invalid two-factor code")
+)
+
+// MFAChallengeCookieName carries the challenge of a provider sign in to
+// the two-factor step, so it never shows up in a URL.
+const MFAChallengeCookieName = "mfa-challenge"
+
+type TwoFactorService struct {
+	userRepo         *repositories.UserRepository
+	recoveryCodeRepo *repositories.RecoveryCodeRepository
+	challengeRepo    *repositories.MFAChallengeRepository
+	config           *config.AppConfig
+}
+
+func NewTwoFactorService(ctx *models.AppContext) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:         repositories.NewUserRepository(ctx.DB),
+		recoveryCodeRepo: repositories.NewRecoveryCodeRepository(ctx.DB),
+		challengeRepo:    repositories.NewMFAChallengeRepository(ctx.DB),
+		config:           ctx.Config.App,
+	}
+}
+
+// IsRequired reports whether the user's role must use two-factor.
+func (tf *TwoFactorService) IsRequired(user *models.User) bool {
+	for _, role := range tf.config.MFARequiredRoles {
+		if role == string(user.Role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Setup generates a fresh secret that stays pending until the user proves
+// their authenticator works by enabling it.
+func (tf *TwoFactorService) Setup(user *models.User) (string, string, error) {
+	secret := utils.GenerateTOTPSecret()
+
+	if err := tf.userRepo.SetPendingTOTPSecret(user.Id, secret); err != nil {
+		return "", "", err
+	}
+
+	return secret, utils.TOTPProvisioningURI(secret, tf.config.MFAIssuer, user.Email), nil
+}
+
+// Enable turns two-factor on after checking a code from the pending
+// secret and returns the plain recovery codes.
+func (tf *TwoFactorService) Enable(user *models.User, code string) ([]string, error) {
+	if user.TOTPSecret == "" {
+		return nil, ErrInvalidMFACode
+	}
+
+	ok, err := tf.VerifyCode(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	if err := tf.userRepo.EnableTOTP(user.Id); err != nil {
+		return nil, err
+	}
+
+	return tf.RegenerateRecoveryCodes(user)
+}
+
+func (tf *TwoFactorService) Disable(user *models.User) error {
+	if err := tf.userRepo.DisableTOTP(user.Id); err != nil {
+		return err
+	}
+
+	return tf.recoveryCodeRepo.DeleteCodes(user.Id)
+}
+
+// VerifyCode accepts each time step only once.
+func (tf *TwoFactorService) VerifyCode(user *models.User, code string) (bool, error) {
+	step := utils.MatchTOTPCode(user.TOTPSecret, code, time.Now())
+	if step == 0 {
+		return false, nil
+	}
+
+	return tf.userRepo.AdvanceTOTPStep(user.Id, step)
+}
+
+func (tf *TwoFactorService) UseRecoveryCode(user *models.User, code string) (bool, error) {
+	return tf.recoveryCodeRepo.UseCode(user.Id, hashRecoveryCode(code))
+}
+
+// VerifyCodeOrRecoveryCode checks whichever of the two the client sent.
+func (tf *TwoFactorService) VerifyCodeOrRecoveryCode(user *models.User, code, recoveryCode string) (bool, error) {
+	if strings.TrimSpace(recoveryCode) != "" {
+		return tf.UseRecoveryCode(user, recoveryCode)
+	}
+
+	return tf.VerifyCode(user, code)
+}
+
+func (tf *TwoFactorService) RegenerateRecoveryCodes(user *models.User) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := utils.GenerateRandomToken()[:10]
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := tf.recoveryCodeRepo.ReplaceCodes(user.Id, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// CreateChallenge returns a short lived signed token that proves the
+// password step succeeded for the user. It completes one login at most,
+// see UseChallenge.
+func (tf *TwoFactorService) CreateChallenge(userId uuid.UUID) (string, error) {
+	now := time.Now()
+
+	return utils.SignJWT(models.MFAChallengeClaims{
+		Id:        utils.GenerateRandomToken(),
+		Subject:   userId.String(),
+		Type:      mfaChallengeType,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Duration(tf.config.MFAChallengeTTLMinutes) * time.Minute).Unix(),
+	}, tf.config.AccessTokenSecret)
+}
+
+func (tf *TwoFactorService) parseChallenge(token string) (*models.MFAChallengeClaims, error) {
+	var claims models.MFAChallengeClaims
+
+	if err := utils.ParseJWT(token, tf.config.AccessTokenSecret, &claims); err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if claims.Type != mfaChallengeType || claims.Id == "" || time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	return &claims, nil
+}
+
+// ResolveChallenge returns the user a challenge token was issued for.
+func (tf *TwoFactorService) ResolveChallenge(token string) (*models.User, error) {
+	claims, err := tf.parseChallenge(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := tf.userRepo.GetUserById(claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive || !user.IsTwoFactorEnabled() {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	return user, nil
+}
+
+// UseChallenge uses the challenge up once its code was accepted. A
+// challenge that already completed a login is ErrInvalidMFAChallenge.
+func (tf *TwoFactorService) UseChallenge(token string) error {
+	claims, err := tf.parseChallenge(token)
+	if err != nil {
+		return err
+	}
+
+	unused, err := tf.challengeRepo.MarkUsed(claims.Id, time.Unix(claims.ExpiresAt, 0))
+	if err != nil {
+		return err
+	}
+	if !unused {
+		return ErrInvalidMFAChallenge
+	}
+
+	return nil
+}
+
+// SetChallengeCookie hands a challenge to the two-factor step in an
+// HttpOnly cookie that only the auth endpoints see.
+func (tf *TwoFactorService) SetChallengeCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     MFAChallengeCookieName,
+		Value:    token,
+		Path:     "/api/auth",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int((time.Duration(tf.config.MFAChallengeTTLMinutes) * time.Minute).Seconds()),
+	})
+}
+
+func (tf *TwoFactorService) ClearChallengeCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     MFAChallengeCookieName,
+		Value:    "",
+		Path:     "/api/auth",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1,
+	})
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return utils.HashString(normalized)
+}
@@ -0,0 +1,61 @@
+package utils
+
+import "strings"
+
+var browserSignatures = []struct {
+	token string
+	name  string
+}{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+}
+
+var platformSignatures = []struct {
+	token string
+	name  string
+}{
+	{"iPad", "iPad"},
+	{"iPhone", "iPhone"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// DescribeDevice turns a User-Agent header into a short human readable
+// label such as "Chrome on Android".
+func DescribeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	browser := ""
+	for _, signature := range browserSignatures {
+		if strings.Contains(userAgent, signature.token) {
+			browser = signature.name
+			break
+		}
+	}
+
+	platform := ""
+	for _, signature := range platformSignatures {
+		if strings.Contains(userAgent, signature.token) {
+			platform = signature.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return userAgent
+	}
+}
@@ -1,23 +1,228 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(pass string) (string, error) {
-	hash, error := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+
+	argon2idPrefix    = "$argon2id$"
+	argon2idSaltBytes = 16
+	argon2idKeyBytes  = 32
+)
+
+var ErrUnknownPasswordHash = errors.New("unknown password hash format")
+
+// PasswordHasher hashes new passwords with the configured parameters and
+// verifies hashes it produced, whatever parameters they were made with.
+type PasswordHasher interface {
+	Hash(pass string) (string, error)
+	Verify(hash, pass string) (bool, error)
+	// Owns reports whether the hash was produced by this algorithm.
+	Owns(hash string) bool
+	// IsWeaker reports whether an owned hash uses weaker parameters than
+	// the hasher is configured with.
+	IsWeaker(hash string) bool
+}
+
+type BcryptHasher struct {
+	Cost int
+}
 
-	if error != nil {
-		fmt.Println("Failed to hash password", error)
+func (bh *BcryptHasher) Hash(pass string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pass), bh.Cost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %v", err)
 	}
 
 	return string(hash), nil
 }
 
+func (bh *BcryptHasher) Verify(hash, pass string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pass))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (bh *BcryptHasher) Owns(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (bh *BcryptHasher) IsWeaker(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < bh.Cost
+}
+
+// Argon2idHasher stores hashes in the PHC string format, for example
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>. Memory is in KiB.
+type Argon2idHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (ah *Argon2idHasher) Hash(pass string) (string, error) {
+	salt := make([]byte, argon2idSaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error generating password salt: %v", err)
+	}
+
+	key := argon2.IDKey([]byte(pass), salt, ah.Iterations, ah.Memory, ah.Parallelism, argon2idKeyBytes)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, ah.Memory, ah.Iterations, ah.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (ah *Argon2idHasher) Verify(hash, pass string) (bool, error) {
+	params, err := parseArgon2idHash(hash)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(pass), params.salt, params.iterations, params.memory, params.parallelism, uint32(len(params.key)))
+
+	return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+}
+
+func (ah *Argon2idHasher) Owns(hash string) bool {
+	return strings.HasPrefix(hash, argon2idPrefix)
+}
+
+func (ah *Argon2idHasher) IsWeaker(hash string) bool {
+	params, err := parseArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+
+	return params.memory < ah.Memory || params.iterations < ah.Iterations || params.parallelism < ah.Parallelism
+}
+
+func parseArgon2idHash(hash string) (*argon2idParams, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != PasswordAlgorithmArgon2id {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	params := &argon2idParams{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	var err error
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrUnknownPasswordHash
+	}
+	if params.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(params.key) == 0 {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	return params, nil
+}
+
+// passwordHasher hashes new passwords. Verification reads the parameters
+// from the hash itself, so verifyHashers only need to recognise formats
+// stored before an algorithm switch.
+var (
+	passwordHasher PasswordHasher = &BcryptHasher{Cost: bcrypt.DefaultCost}
+	verifyHashers                 = []PasswordHasher{&BcryptHasher{}, &Argon2idHasher{}}
+)
+
+// SetPasswordHasher chooses the hasher for new passwords. Call it once at
+// startup, before serving requests.
+func SetPasswordHasher(hasher PasswordHasher) {
+	passwordHasher = hasher
+}
+
+// Bounds for the argon2id settings. Every login hashes once, so memory is
+// capped at 1 GiB, and parallelism must fit the uint8 of the PHC string.
+const (
+	maxArgon2idMemoryKiB   = 1024 * 1024
+	maxArgon2idIterations  = 100
+	maxArgon2idParallelism = 255
+)
+
+// NewPasswordHasher builds the hasher for the configured algorithm. The
+// settings are checked before they are narrowed to the argon2 types.
+func NewPasswordHasher(algorithm string, bcryptCost, argonMemoryKiB, argonIterations, argonParallelism int) (PasswordHasher, error) {
+	switch algorithm {
+	case PasswordAlgorithmBcrypt:
+		if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return &BcryptHasher{Cost: bcryptCost}, nil
+	case PasswordAlgorithmArgon2id:
+		if argonParallelism < 1 || argonParallelism > maxArgon2idParallelism {
+			return nil, fmt.Errorf("argon2id parallelism must be between 1 and %d", maxArgon2idParallelism)
+		}
+		// argon2 needs at least 8 KiB per lane.
+		if argonMemoryKiB < 8*argonParallelism || argonMemoryKiB > maxArgon2idMemoryKiB {
+			return nil, fmt.Errorf("argon2id memory must be between %d and %d KiB", 8*argonParallelism, maxArgon2idMemoryKiB)
+		}
+		if argonIterations < 1 || argonIterations > maxArgon2idIterations {
+			return nil, fmt.Errorf("argon2id iterations must be between 1 and %d", maxArgon2idIterations)
+		}
+		return &Argon2idHasher{
+			Memory:      uint32(argonMemoryKiB),
+			Iterations:  uint32(argonIterations),
+			Parallelism: uint8(argonParallelism),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown password hash algorithm %q", algorithm)
+}
+
+func HashPassword(pass string) (string, error) {
+	return passwordHasher.Hash(pass)
+}
+
+// IsPasswordEqualHash picks the algorithm from the hash prefix, so hashes
+// made before an algorithm switch keep working.
 func IsPasswordEqualHash(hash, pass string) bool {
-	error := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pass))
+	for _, hasher := range verifyHashers {
+		if hasher.Owns(hash) {
+			ok, err := hasher.Verify(hash, pass)
+			if err != nil {
+				log.Printf("ERROR: Failed to verify password: %v", err)
+			}
+			return ok
+		}
+	}
+
+	return false
+}
 
-	return error == nil
+// PasswordNeedsRehash reports whether the hash was made with another
+// algorithm or weaker parameters than new passwords get.
+func PasswordNeedsRehash(hash string) bool {
+	return !passwordHasher.Owns(hash) || passwordHasher.IsWeaker(hash)
 }
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// SignJWT encodes the claims as an HS256 signed JSON Web Token.
+func SignJWT(claims any, secret string) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	return unsigned + "." + jwtSignature(unsigned, secret), nil
+}
+
+// ParseJWT checks the HS256 signature and decodes the payload into claims.
+// Expiry is left to the caller.
+func ParseJWT(token, secret string, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return ErrInvalidToken
+	}
+
+	expected := jwtSignature(parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func jwtSignature(unsigned, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxLocales caps how many language ranges of a header are looked at.
+const maxLocales = 10
+
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// NormalizeLocale returns a BCP 47 tag such as "pt-BR" in its usual case,
+// or false when it is not one. Scripts are title case and regions upper
+// case, so "zh-hant-tw" becomes "zh-Hant-TW".
+func NormalizeLocale(locale string) (string, bool) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if len(locale) > 35 || !localePattern.MatchString(locale) {
+		return "", false
+	}
+
+	parts := strings.Split(strings.ToLower(locale), "-")
+	for i := 1; i < len(parts); i++ {
+		switch {
+		case len(parts[i]) == 4 && i == 1:
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		case len(parts[i]) == 2:
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+
+	return strings.Join(parts, "-"), true
+}
+
+// GetLocales negotiates the request's languages into a fallback chain,
+// most preferred first. A ?locale= parameter comes before Accept-Language.
+// Every tag is followed by its shorter forms, so "de-AT, en;q=0.5" gives
+// de-AT, de, en. Callers fall back to the untranslated text at the end.
+func GetLocales(r *http.Request) []string {
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	ranges := []weighted{}
+
+	if locale, ok := NormalizeLocale(r.URL.Query().Get("locale")); ok {
+		ranges = append(ranges, weighted{locale, 2})
+	}
+
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		if len(ranges) > maxLocales {
+			break
+		}
+
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale, ok := NormalizeLocale(tag)
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, weighted{locale, quality})
+	}
+
+	slices.SortStableFunc(ranges, func(a, b weighted) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		}
+		return 0
+	})
+
+	locales := []string{}
+	for _, rng := range ranges {
+		parts := strings.Split(rng.locale, "-")
+		for i := len(parts); i > 0; i-- {
+			locale := strings.Join(parts[:i], "-")
+			if !slices.Contains(locales, locale) {
+				locales = append(locales, locale)
+			}
+		}
+	}
+
+	return locales
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+		ok     bool
+	}{
+		{"en", "en", true},
+		{"EN", "en", true},
+		{"pt-br", "pt-BR", true},
+		{"pt_BR", "pt-BR", true},
+		{" de-at ", "de-AT", true},
+		{"zh-hant-tw", "zh-Hant-TW", true},
+		{"SR-LATN", "sr-Latn", true},
+		{"es-419", "es-419", true},
+		{"fil", "fil", true},
+		{"", "", false},
+		{"e", "", false},
+		{"english", "", false},
+		{"en-", "", false},
+		{"en--us", "", false},
+		{"en-u", "", false},
+		{"*", "", false},
+		{"en;q=0.5", "", false},
+		{"en-abcdefghi", "", false},
+		{"en-aaaaaaaa-bbbbbbbb-cccccccc-dddd-ee", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			got, ok := NormalizeLocale(tt.locale)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("NormalizeLocale(%q) = %q, %v, want %q, %v", tt.locale, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGetLocales(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           []string
+	}{
+		{"nothing asked for", "", "", []string{}},
+		{"shorter forms follow a tag", "", "de-AT", []string{"de-AT", "de"}},
+		{"every subtag is dropped in turn", "", "zh-Hant-TW", []string{"zh-Hant-TW", "zh-Hant", "zh"}},
+		{"ordered by quality", "", "en;q=0.5, de-AT, fr;q=0.8", []string{"de-AT", "de", "fr", "en"}},
+		{"equal quality keeps header order", "", "fr, en, it;q=1", []string{"fr", "en", "it"}},
+		{"quality above one comes first", "", "en, de;q=1.5", []string{"de", "en"}},
+		{"zero quality is excluded", "", "de, en;q=0", []string{"de"}},
+		{"negative quality is excluded", "", "de, en;q=-1", []string{"de"}},
+		{"invalid quality is skipped", "", "de;q=high, en", []string{"en"}},
+		{"invalid tags are skipped", "", "*, klingon!, en_GB;q=0.3", []string{"en-GB", "en"}},
+		{"fallback is listed once", "", "de-AT, de-CH;q=0.9, de;q=0.1", []string{"de-AT", "de", "de-CH"}},
+		{"general tag before its region", "", "de, de-AT;q=0.5", []string{"de", "de-AT"}},
+		{"query parameter comes first", "locale=pt_br", "de, pt;q=0.1", []string{"pt-BR", "pt", "de"}},
+		{"query parameter beats quality above one", "locale=it", "de;q=1.9", []string{"it", "de"}},
+		{"invalid query parameter is ignored", "locale=%2A", "de", []string{"de"}},
+		{"query parameter alone", "locale=fr-CA", "", []string{"fr-CA", "fr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/menu?"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			if got := GetLocales(r); !slices.Equal(got, tt.want) {
+				t.Errorf("GetLocales() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// NormalizePhone strips the usual separators and returns the number in
+// E.164 form, or false when it is not an international number.
+func NormalizePhone(phone string) (string, bool) {
+	phone = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(strings.TrimSpace(phone))
+	if strings.HasPrefix(phone, "00") {
+		phone = "+" + phone[2:]
+	}
+
+	if !e164Pattern.MatchString(phone) {
+		return "", false
+	}
+
+	return phone, true
+}
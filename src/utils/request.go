@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// trustedProxies are the networks whose X-Forwarded-For header is
+// believed. Empty by default, so the header is ignored.
+var trustedProxies []*net.IPNet
+
+// SetTrustedProxies parses the IP addresses and CIDR ranges of the reverse
+// proxies in front of the server. Call it once at startup, before serving
+// requests.
+func SetTrustedProxies(proxies []string) error {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q: %v", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+
+	trustedProxies = networks
+	return nil
+}
+
+func isTrustedProxy(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetClientIP returns the address the request came from. X-Forwarded-For
+// is only read when the connection comes from a trusted proxy, and then
+// from the right, skipping the trusted proxies that appended to it. Entries
+// further left are set by the client and could be anything.
+func GetClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if !isTrustedProxy(host) {
+		return host
+	}
+
+	hops := []string{}
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !isTrustedProxy(hops[i]) {
+			return hops[i]
+		}
+		host = hops[i]
+	}
+
+	return host
+}
+
+// GetPagination reads the page and page_size query parameters, falling
+// back to sane defaults for missing or invalid values.
+func GetPagination(r *http.Request) (page, pageSize int) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err = strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return page, pageSize
+}
+
+// Truncate cuts the value to at most max bytes without splitting a
+// character, and replaces invalid UTF-8 from the client, so the result
+// can always be stored.
+func Truncate(value string, max int) string {
+	value = strings.ToValidUTF8(value, "")
+
+	if len(value) <= max {
+		return value
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+
+	return value[:cut]
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpPeriod = 30
+	totpDigits = 6
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func GenerateTOTPSecret() string {
+	bytes := make([]byte, 20)
+	rand.Read(bytes)
+
+	return totpEncoding.EncodeToString(bytes)
+}
+
+// TOTPProvisioningURI builds the otpauth:// URI authenticator apps read
+// from a QR code.
+func TOTPProvisioningURI(secret, issuer, account string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// MatchTOTPCode checks the code against the current step and one step of
+// clock drift on either side. It returns the matching step, or 0.
+func MatchTOTPCode(secret, code string, now time.Time) int64 {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0
+	}
+
+	current := TOTPStep(now)
+	for _, step := range []int64{current, current - 1, current + 1} {
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0
+		}
+
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,279 @@
+// Command mock-oidc is a minimal OpenID Connect provider for trying the
+// social login flow locally. It signs in whoever fills in the form, so
+// never expose it outside a development machine.
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const keyId = "mock-1"
+
+type authorization struct {
+	clientId      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	subject       string
+	email         string
+	emailVerified bool
+	name          string
+	expiresAt     time.Time
+}
+
+type server struct {
+	issuer       string
+	clientId     string
+	clientSecret string
+	key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]*authorization
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<html>
+<head><title>Mock OIDC sign in</title></head>
+<body style="font-family: sans-serif; max-width: 24rem; margin: 4rem auto">
+	<h1>Mock OIDC</h1>
+	<form method="post">
+		{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+		{{end}}
+		<p><label>Email<br><input name="email" value="{{.Email}}" required></label></p>
+		<p><label>Name<br><input name="name" value="{{.Name}}"></label></p>
+		<p><label><input type="checkbox" name="email_verified" value="true" checked> Email verified</label></p>
+		<p><button type="submit">Sign in</button></p>
+	</form>
+</body>
+</html>`))
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9090", "issuer URL, must match OIDC_<NAME>_ISSUER")
+	clientId := flag.String("client-id", "restaurant", "accepted client id")
+	clientSecret := flag.String("client-secret", "mock-secret", "accepted client secret, empty for a public client")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal("Error generating signing key:", err)
+	}
+
+	s := &server{
+		issuer:       strings.TrimRight(*issuer, "/"),
+		clientId:     *clientId,
+		clientSecret: *clientSecret,
+		key:          key,
+		codes:        map[string]*authorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("GET /jwks", s.jwks)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("POST /token", s.token)
+
+	fmt.Printf("Mock OIDC provider %s listening on %s\n", s.issuer, *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func (s *server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                s.issuer,
+		"authorization_endpoint":                s.issuer + "/authorize",
+		"token_endpoint":                        s.issuer + "/token",
+		"jwks_uri":                              s.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+func (s *server) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize shows the sign in form on GET and issues a code on POST.
+func (s *server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	params := map[string]string{}
+	for _, name := range []string{"response_type", "client_id", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method"} {
+		params[name] = r.Form.Get(name)
+	}
+
+	if params["response_type"] != "code" || params["client_id"] != s.clientId || params["redirect_uri"] == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	if params["code_challenge"] == "" || params["code_challenge_method"] != "S256" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		loginPage.Execute(w, map[string]any{
+			"Params": params,
+			"Email":  r.Form.Get("login_hint"),
+			"Name":   "",
+		})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(r.Form.Get("email")))
+	if email == "" {
+		http.Error(w, "email is required", http.StatusBadRequest)
+		return
+	}
+
+	code := randomHex(16)
+	subject := sha256.Sum256([]byte(email))
+
+	s.mu.Lock()
+	s.codes[code] = &authorization{
+		clientId:      params["client_id"],
+		redirectURI:   params["redirect_uri"],
+		codeChallenge: params["code_challenge"],
+		nonce:         params["nonce"],
+		subject:       hex.EncodeToString(subject[:8]),
+		email:         email,
+		emailVerified: r.Form.Get("email_verified") == "true",
+		name:          strings.TrimSpace(r.Form.Get("name")),
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	s.mu.Unlock()
+
+	redirect := params["redirect_uri"] + "?" + url.Values{"code": {code}, "state": {params["state"]}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (s *server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	clientId, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientId, _ = url.QueryUnescape(clientId)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientId, clientSecret = r.Form.Get("client_id"), r.Form.Get("client_secret")
+	}
+
+	if clientId != s.clientId || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.clientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	if r.Form.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	s.mu.Lock()
+	auth, found := s.codes[r.Form.Get("code")]
+	delete(s.codes, r.Form.Get("code"))
+	s.mu.Unlock()
+
+	if !found || time.Now().After(auth.expiresAt) || auth.clientId != clientId || auth.redirectURI != r.Form.Get("redirect_uri") {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	verifier := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(verifier[:]) != auth.codeChallenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	idToken, err := s.sign(map[string]any{
+		"iss":            s.issuer,
+		"sub":            auth.subject,
+		"aud":            clientId,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          auth.nonce,
+		"email":          auth.email,
+		"email_verified": auth.emailVerified,
+		"name":           auth.name,
+	})
+	if err != nil {
+		tokenError(w, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomHex(32),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (s *server) sign(claims map[string]any) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyId})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func randomHex(size int) string {
+	bytes := make([]byte, size)
+	rand.Read(bytes)
+
+	return hex.EncodeToString(bytes)
+}